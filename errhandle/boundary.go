@@ -0,0 +1,297 @@
+package errhandle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Boundary kinds
+const (
+	// boundaryGRPC matches methods implementing a generated gRPC `*Server` interface
+	boundaryGRPC = "grpc"
+	// boundaryHTTP matches http.HandlerFunc handlers, and handlers with its parameters that return an error
+	boundaryHTTP = "http"
+	// boundarySignature matches the functions of BoundarySettings.Signatures
+	boundarySignature = "signature"
+)
+
+var boundaryKinds = []string{boundaryGRPC, boundaryHTTP}
+
+var boundaryNames = map[string]string{
+	boundaryGRPC:      "gRPC handler",
+	boundaryHTTP:      "HTTP handler",
+	boundarySignature: "boundary function",
+}
+
+// boundaryKind returns the boundary kind of a function declaration, or "" if it is not a boundary
+func (l *Linter) boundaryKind(pass *analysis.Pass, decl *ast.FuncDecl, servers []*types.Interface) string {
+	if decl == nil {
+		return ""
+	}
+	fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return ""
+	}
+	sig := fn.Type().(*types.Signature)
+
+	if slices.Contains(l.settings.Boundary.Kinds, boundaryGRPC) && sig.Recv() != nil && implementsServerMethod(sig.Recv().Type(), fn.Name(), servers) {
+		return boundaryGRPC
+	}
+	if slices.Contains(l.settings.Boundary.Kinds, boundaryHTTP) && isHTTPHandler(sig) {
+		return boundaryHTTP
+	}
+	if slices.Contains(l.settings.Boundary.Signatures, signatureString(sig)) {
+		return boundarySignature
+	}
+	return ""
+}
+
+// grpcServers returns the `*Server` interfaces declared in the packages imported by
+// the analyzed package, as generated by protoc-gen-go-grpc: those with a
+// mustEmbedUnimplemented<Name> method or an Unimplemented<Name> type alongside
+func (l *Linter) grpcServers(pass *analysis.Pass) []*types.Interface {
+	if !slices.Contains(l.settings.Boundary.Kinds, boundaryGRPC) {
+		return nil
+	}
+	var servers []*types.Interface
+	for _, imp := range pass.Pkg.Imports() {
+		scope := imp.Scope()
+		for _, name := range scope.Names() {
+			if !strings.HasSuffix(name, "Server") {
+				continue
+			}
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			iface, ok := tn.Type().Underlying().(*types.Interface)
+			if !ok || iface.NumMethods() == 0 {
+				continue
+			}
+			if hasMethod(iface, "mustEmbedUnimplemented"+name) || scope.Lookup("Unimplemented"+name) != nil {
+				servers = append(servers, iface)
+			}
+		}
+	}
+	return servers
+}
+
+// hasMethod reports whether iface has a method named name, exported or not
+func hasMethod(iface *types.Interface, name string) bool {
+	for i := 0; i < iface.NumMethods(); i++ {
+		if iface.Method(i).Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// implementsServerMethod reports whether recv implements one of servers, including the method name
+func implementsServerMethod(recv types.Type, method string, servers []*types.Interface) bool {
+	for _, iface := range servers {
+		obj, _, _ := types.LookupFieldOrMethod(iface, false, nil, method)
+		if obj == nil {
+			continue
+		}
+		// Generated servers embed an Unimplemented struct, so either the value or the pointer implements it
+		if types.Implements(recv, iface) {
+			return true
+		}
+		if _, ok := recv.(*types.Pointer); !ok && types.Implements(types.NewPointer(recv), iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTTPHandler reports whether sig is func(http.ResponseWriter, *http.Request), the
+// signature of http.HandlerFunc, or the same returning an error
+func isHTTPHandler(sig *types.Signature) bool {
+	params, results := sig.Params(), sig.Results()
+	if params.Len() != 2 || results.Len() > 1 {
+		return false
+	}
+	if !isNamedType(params.At(0).Type(), "net/http", "ResponseWriter") {
+		return false
+	}
+	ptr, ok := params.At(1).Type().(*types.Pointer)
+	if !ok || !isNamedType(ptr.Elem(), "net/http", "Request") {
+		return false
+	}
+	return results.Len() == 0 || types.Identical(results.At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+// signatureString formats sig without its receiver and parameter names, with full
+// package paths, e.g. "func(net/http.ResponseWriter, *net/http.Request) error"
+func signatureString(sig *types.Signature) string {
+	tupleString := func(t *types.Tuple, variadic bool) []string {
+		var list []string
+		for i := 0; i < t.Len(); i++ {
+			typ := t.At(i).Type()
+			if variadic && i == t.Len()-1 {
+				list = append(list, "..."+types.TypeString(typ.(*types.Slice).Elem(), nil))
+				continue
+			}
+			list = append(list, types.TypeString(typ, nil))
+		}
+		return list
+	}
+
+	s := "func(" + strings.Join(tupleString(sig.Params(), sig.Variadic()), ", ") + ")"
+	switch results := tupleString(sig.Results(), false); len(results) {
+	case 0:
+	case 1:
+		s += " " + results[0]
+	default:
+		s += " (" + strings.Join(results, ", ") + ")"
+	}
+	return s
+}
+
+func isNamedType(t types.Type, pkgPath, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+// checkBoundary reports errors returned from a boundary function that are not
+// produced by one of the configured translators
+func (l *Linter) checkBoundary(pass *analysis.Pass, fn *funcIndex, site returnSite, kind string) {
+	ret := site.ret
+
+	// Direct returns like "return s.client.SayHello(ctx, req)"
+	if len(ret.Results) == 1 {
+		if call, ok := ret.Results[0].(*ast.CallExpr); ok {
+			if _, ok := pass.TypesInfo.TypeOf(call).(*types.Tuple); ok {
+				if !l.isTranslated(pass, fn, call, ret.Pos()) {
+					l.reportUntranslated(pass, call, kind)
+				}
+				return
+			}
+		}
+	}
+
+	for _, result := range ret.Results {
+		if !l.isErrorType(pass, result) {
+			continue
+		}
+		if !l.isTranslated(pass, fn, result, ret.Pos()) {
+			l.reportUntranslated(pass, result, kind)
+		}
+	}
+}
+
+// isTranslated reports whether the error value of expr, as of pos, is nil or
+// produced by a translator. Variables are resolved through the def-use index of fn.
+func (l *Linter) isTranslated(pass *analysis.Pass, fn *funcIndex, expr ast.Expr, pos token.Pos) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		callee, ok := typeutil.Callee(pass.TypesInfo, e).(*types.Func)
+		return ok && slices.Contains(l.settings.Boundary.Translators, callee.FullName())
+	case *ast.Ident:
+		if isNil(pass, e) {
+			return true
+		}
+		a, ok := fn.latest(pass.TypesInfo.ObjectOf(e), pos)
+		if !ok {
+			return false
+		}
+		return l.isTranslated(pass, fn, a.rhs, a.pos)
+	}
+	return false
+}
+
+func (l *Linter) reportUntranslated(pass *analysis.Pass, expr ast.Expr, kind string) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      expr.Pos(),
+		Category: "errhandle",
+		Message:  fmt.Sprintf("error returned from %s must be translated by %s", boundaryNames[kind], strings.Join(l.settings.Boundary.Translators, ", ")),
+	})
+}
+
+// checkHandlerWrites reports errors written to the response of an http.HandlerFunc
+// boundary, which returns nothing, by calls other than translators, e.g.
+// http.Error(w, err.Error(), http.StatusInternalServerError)
+func (l *Linter) checkHandlerWrites(pass *analysis.Pass, fn *funcIndex) {
+	decl := fn.decl
+	if decl == nil || decl.Body == nil {
+		return
+	}
+	params := decl.Type.Params.List
+	if len(params) == 0 || len(params[0].Names) == 0 {
+		return
+	}
+	w := pass.TypesInfo.Defs[params[0].Names[0]]
+	if w == nil {
+		return
+	}
+	isWriter := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[ident] == w
+	}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if callee, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func); ok && slices.Contains(l.settings.Boundary.Translators, callee.FullName()) {
+			return false
+		}
+
+		// Calls writing to the response take the writer or are its methods
+		writes := slices.ContainsFunc(call.Args, isWriter)
+		if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && isWriter(sel.X) {
+			writes = true
+		}
+		if !writes {
+			return true
+		}
+		for _, arg := range call.Args {
+			if expr := l.errorIn(pass, arg); expr != nil {
+				pass.Report(analysis.Diagnostic{
+					Pos:      expr.Pos(),
+					Category: "errhandle",
+					Message:  fmt.Sprintf("error written by %s must be translated by %s", boundaryNames[boundaryHTTP], strings.Join(l.settings.Boundary.Translators, ", ")),
+				})
+			}
+		}
+		return true
+	})
+}
+
+// errorIn returns the first non-nil error value within expr, outside translator calls
+func (l *Linter) errorIn(pass *analysis.Pass, expr ast.Expr) ast.Expr {
+	var found ast.Expr
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		e, ok := n.(ast.Expr)
+		if !ok {
+			return true
+		}
+		if call, ok := e.(*ast.CallExpr); ok {
+			if callee, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func); ok && slices.Contains(l.settings.Boundary.Translators, callee.FullName()) {
+				return false
+			}
+		}
+		tv, ok := pass.TypesInfo.Types[e]
+		if ok && tv.IsValue() && !tv.IsNil() && types.Implements(tv.Type, errorInterface) {
+			found = e
+			return false
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,430 @@
+package errhandle
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// wrapperImport describes how a file refers to the wrapper package
+type wrapperImport struct {
+	pkg       *types.Package // nil if the analyzed package does not depend on the wrapper package
+	qualifier string         // local name of the wrapper package, empty for dot imports
+	imported  bool           // whether the file imports the wrapper package, required for suggested fixes
+}
+
+// has reports whether the wrapper package exports the function name.
+// Without type information about the wrapper, only the standard Is/As helpers are assumed.
+func (w wrapperImport) has(name string) bool {
+	if w.pkg == nil {
+		return name == "Is" || name == "As"
+	}
+	_, ok := w.pkg.Scope().Lookup(name).(*types.Func)
+	return ok
+}
+
+// display returns the helper name used in diagnostic messages, e.g. "errors.Is"
+func (w wrapperImport) display(name string) string {
+	if w.pkg == nil {
+		return "errors." + name
+	}
+	return w.pkg.Name() + "." + name
+}
+
+// qualify returns the helper name as it must be written in the file
+func (w wrapperImport) qualify(name string) string {
+	if w.qualifier == "" {
+		return name
+	}
+	return w.qualifier + "." + name
+}
+
+func (l *Linter) lookupWrapper(pass *analysis.Pass, file *ast.File) wrapperImport {
+	var w wrapperImport
+	path := l.wrapperPackage()
+	for _, imp := range pass.Pkg.Imports() {
+		if imp.Path() == path {
+			w.pkg = imp
+			break
+		}
+	}
+	if w.pkg == nil {
+		return w
+	}
+
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, "\"") != path {
+			continue
+		}
+		switch {
+		case imp.Name == nil:
+			w.qualifier, w.imported = w.pkg.Name(), true
+		case imp.Name.Name == ".":
+			w.qualifier, w.imported = "", true
+		case imp.Name.Name != "_":
+			w.qualifier, w.imported = imp.Name.Name, true
+		}
+	}
+	return w
+}
+
+// checkComparisons reports == / != comparisons, value switches and type assertions on
+// error values, which silently stop matching once errors are wrapped, and suggests the
+// equivalent helper of the wrapper package.
+func (l *Linter) checkComparisons(pass *analysis.Pass, inspect *inspector.Inspector) {
+	if !l.settings.CheckComparisons {
+		return
+	}
+
+	var w wrapperImport
+	nodeFilter := []ast.Node{
+		(*ast.File)(nil),
+		(*ast.FuncDecl)(nil),
+		(*ast.BinaryExpr)(nil),
+		(*ast.SwitchStmt)(nil),
+		(*ast.TypeAssertExpr)(nil),
+	}
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch node := n.(type) {
+		case *ast.File:
+			if !l.ruleEnabled(pass, ruleComparison, node.Pos()) {
+				return false
+			}
+			w = l.lookupWrapper(pass, node)
+		case *ast.FuncDecl:
+			// Is methods implement the very comparison errors.Is relies on
+			return !isErrorIsMethod(pass, node)
+		case *ast.BinaryExpr:
+			l.checkErrorComparison(pass, node, w)
+		case *ast.SwitchStmt:
+			l.checkErrorSwitch(pass, node, w)
+		case *ast.TypeAssertExpr:
+			l.checkErrorAssertion(pass, node, stack, w)
+		}
+		return true
+	})
+}
+
+func (l *Linter) checkErrorComparison(pass *analysis.Pass, expr *ast.BinaryExpr, w wrapperImport) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	if isNil(pass, expr.X) || isNil(pass, expr.Y) {
+		return
+	}
+	if !isErrorInterface(pass, expr.X) && !isErrorInterface(pass, expr.Y) {
+		return
+	}
+	if l.isCauseCall(pass, expr.X) || l.isCauseCall(pass, expr.Y) {
+		return // already compared after unwrapping
+	}
+
+	// The sentinel is usually a package-level variable, e.g. sql.ErrNoRows
+	errExpr, target := expr.X, expr.Y
+	if isPackageLevelVar(pass, errExpr) && !isPackageLevelVar(pass, target) {
+		errExpr, target = target, errExpr
+	}
+
+	diag := analysis.Diagnostic{
+		Pos:      expr.Pos(),
+		Category: "errhandle",
+	}
+	switch {
+	case w.has("Is"):
+		diag.Message = fmt.Sprintf("comparing errors with %s does not match wrapped errors, use %s", expr.Op, w.display("Is"))
+		if w.imported {
+			newText := fmt.Sprintf("%s(%s, %s)", w.qualify("Is"), render(pass.Fset, errExpr), render(pass.Fset, target))
+			if expr.Op == token.NEQ {
+				newText = "!" + newText
+			}
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: fmt.Sprintf("Replace with %s", w.display("Is")),
+				TextEdits: []analysis.TextEdit{{
+					Pos:     expr.Pos(),
+					End:     expr.End(),
+					NewText: []byte(newText),
+				}},
+			}}
+		}
+	case w.has("Cause"):
+		diag.Message = fmt.Sprintf("comparing errors with %s does not match wrapped errors, use %s", expr.Op, w.display("Cause"))
+		if w.imported {
+			diag.SuggestedFixes = l.causeFix(w, errExpr)
+		}
+	default:
+		return // the wrapper library offers no way to unwrap
+	}
+	pass.Report(diag)
+}
+
+// checkErrorSwitch reports the cases of a switch on an error value, which compare with ==
+func (l *Linter) checkErrorSwitch(pass *analysis.Pass, stmt *ast.SwitchStmt, w wrapperImport) {
+	if stmt.Tag == nil || !isErrorInterface(pass, stmt.Tag) || l.isCauseCall(pass, stmt.Tag) {
+		return
+	}
+
+	var helper string
+	var fixes []analysis.SuggestedFix
+	switch {
+	case w.has("Is"):
+		helper = "Is"
+		if w.imported {
+			fixes = l.switchIsFix(pass, w, stmt)
+		}
+	case w.has("Cause"):
+		helper = "Cause"
+		if w.imported {
+			fixes = l.causeFix(w, stmt.Tag)
+		}
+	default:
+		return
+	}
+
+	// Every case shares the fix of the whole switch
+	for _, clause := range stmt.Body.List {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			if isNil(pass, expr) {
+				continue
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:            expr.Pos(),
+				Category:       "errhandle",
+				Message:        fmt.Sprintf("comparing errors in a switch case does not match wrapped errors, use %s", w.display(helper)),
+				SuggestedFixes: fixes,
+			})
+		}
+	}
+}
+
+// switchIsFix rewrites a switch on an error value into a tagless switch whose cases call
+// the wrapper's Is function. The value is repeated in every case, so it must be a variable.
+func (l *Linter) switchIsFix(pass *analysis.Pass, w wrapperImport, stmt *ast.SwitchStmt) []analysis.SuggestedFix {
+	if !isVarRef(stmt.Tag) {
+		return nil
+	}
+	tag := render(pass.Fset, stmt.Tag)
+
+	edits := []analysis.TextEdit{{Pos: stmt.Tag.Pos(), End: stmt.Body.Lbrace}}
+	for _, clause := range stmt.Body.List {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			newText := fmt.Sprintf("%s(%s, %s)", w.qualify("Is"), tag, render(pass.Fset, expr))
+			if isNil(pass, expr) {
+				newText = tag + " == nil"
+			}
+			edits = append(edits, analysis.TextEdit{Pos: expr.Pos(), End: expr.End(), NewText: []byte(newText)})
+		}
+	}
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Replace with %s", w.display("Is")),
+		TextEdits: edits,
+	}}
+}
+
+func (l *Linter) checkErrorAssertion(pass *analysis.Pass, expr *ast.TypeAssertExpr, stack []ast.Node, w wrapperImport) {
+	if !isErrorInterface(pass, expr.X) || l.isCauseCall(pass, expr.X) {
+		return
+	}
+
+	kind := "type assertion"
+	if expr.Type == nil {
+		kind = "type switch"
+	}
+
+	diag := analysis.Diagnostic{
+		Pos:      expr.Pos(),
+		Category: "errhandle",
+	}
+	switch {
+	case w.has("Cause"):
+		diag.Message = fmt.Sprintf("%s on error does not match wrapped errors, use %s", kind, w.display("Cause"))
+		if w.imported {
+			diag.SuggestedFixes = l.causeFix(w, expr.X)
+		}
+	case w.has("As"):
+		diag.Message = fmt.Sprintf("%s on error does not match wrapped errors, use %s", kind, w.display("As"))
+		if w.imported {
+			diag.SuggestedFixes = asFix(pass, w, expr, stack)
+		}
+	default:
+		return
+	}
+	pass.Report(diag)
+}
+
+// asFix rewrites the type assertion on top of stack to the wrapper's As function, when it
+// is a type switch without a bound variable, or a comma-ok assignment like
+//
+//	target, ok := err.(*MyError)
+//
+// which becomes
+//
+//	var target *MyError
+//	ok := errors.As(err, &target)
+func asFix(pass *analysis.Pass, w wrapperImport, expr *ast.TypeAssertExpr, stack []ast.Node) []analysis.SuggestedFix {
+	if len(stack) < 3 {
+		return nil
+	}
+	value := render(pass.Fset, expr.X)
+	as := func(target string) string {
+		return fmt.Sprintf("%s(%s, %s)", w.qualify("As"), value, target)
+	}
+
+	var edits []analysis.TextEdit
+	if expr.Type == nil {
+		// Bound variables have the type of each case, which errors.As cannot provide
+		if _, ok := stack[len(stack)-2].(*ast.ExprStmt); !ok || !isVarRef(expr.X) {
+			return nil
+		}
+		stmt := stack[len(stack)-3].(*ast.TypeSwitchStmt)
+		edits = append(edits, analysis.TextEdit{Pos: stmt.Assign.Pos(), End: stmt.Body.Lbrace})
+		for _, clause := range stmt.Body.List {
+			for _, typ := range clause.(*ast.CaseClause).List {
+				newText := as("new(" + render(pass.Fset, typ) + ")")
+				if isNil(pass, typ) {
+					newText = value + " == nil"
+				}
+				edits = append(edits, analysis.TextEdit{Pos: typ.Pos(), End: typ.End(), NewText: []byte(newText)})
+			}
+		}
+	} else {
+		assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+			return nil
+		}
+		target, ok1 := assign.Lhs[0].(*ast.Ident)
+		result, ok2 := assign.Lhs[1].(*ast.Ident)
+		if !ok1 || !ok2 {
+			return nil
+		}
+		declared := func(ident *ast.Ident) bool {
+			return assign.Tok == token.DEFINE && pass.TypesInfo.Defs[ident] != nil
+		}
+
+		var decl, call string
+		if target.Name == "_" {
+			if result.Name == "_" {
+				return nil
+			}
+			call = as("new(" + render(pass.Fset, expr.Type) + ")")
+		} else {
+			// The declaration of the target needs a statement of its own
+			switch stack[len(stack)-3].(type) {
+			case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			default:
+				return nil
+			}
+			if declared(target) {
+				indent := strings.Repeat("\t", pass.Fset.Position(assign.Pos()).Column-1)
+				decl = fmt.Sprintf("var %s %s\n%s", target.Name, render(pass.Fset, expr.Type), indent)
+			} else if !types.Identical(pass.TypesInfo.TypeOf(target), pass.TypesInfo.TypeOf(expr.Type)) {
+				return nil // errors.As would set the target to a value of another type
+			}
+			call = as("&" + target.Name)
+		}
+		newText := decl + call
+		if result.Name != "_" {
+			op := "="
+			if declared(result) {
+				op = ":="
+			}
+			newText = decl + result.Name + " " + op + " " + call
+		}
+		edits = append(edits, analysis.TextEdit{Pos: assign.Pos(), End: assign.End(), NewText: []byte(newText)})
+	}
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Replace with %s", w.display("As")),
+		TextEdits: edits,
+	}}
+}
+
+// causeFix wraps expr in a call to the wrapper's Cause function
+func (l *Linter) causeFix(w wrapperImport, expr ast.Expr) []analysis.SuggestedFix {
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Unwrap with %s", w.display("Cause")),
+		TextEdits: []analysis.TextEdit{
+			{Pos: expr.Pos(), End: expr.Pos(), NewText: []byte(w.qualify("Cause") + "(")},
+			{Pos: expr.End(), End: expr.End(), NewText: []byte(")")},
+		},
+	}}
+}
+
+// isCauseCall reports whether expr is a call to the wrapper's Cause function
+func (l *Linter) isCauseCall(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Name() == "Cause" && fn.Pkg() != nil && fn.Pkg().Path() == l.wrapperPackage()
+}
+
+// isErrorIsMethod reports whether funcDecl is an `Is(error) bool` method
+func isErrorIsMethod(pass *analysis.Pass, funcDecl *ast.FuncDecl) bool {
+	if funcDecl.Recv == nil || funcDecl.Name.Name != "Is" {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	params := fn.Type().(*types.Signature).Params()
+	return params.Len() == 1 && types.Implements(params.At(0).Type(), errorInterface)
+}
+
+// isVarRef reports whether expr is a variable or a field of one, which can be repeated
+// without evaluating anything else
+func isVarRef(expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isVarRef(e.X)
+	}
+	return false
+}
+
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	return ok && tv.IsNil()
+}
+
+// isErrorInterface reports whether expr has an interface type implementing error
+func isErrorInterface(pass *analysis.Pass, expr ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(expr)
+	return t != nil && types.IsInterface(t) && types.Implements(t, errorInterface)
+}
+
+// isPackageLevelVar reports whether expr refers to a package-level variable such as io.EOF
+func isPackageLevelVar(pass *analysis.Pass, expr ast.Expr) bool {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return false
+	}
+	v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	return ok && v.Pkg() != nil && v.Pkg().Scope().Lookup(v.Name()) == v
+}
+
+// render prints expr as it appears in source
+func render(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return types.ExprString(expr)
+	}
+	return buf.String()
+}
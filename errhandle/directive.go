@@ -0,0 +1,216 @@
+package errhandle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const directivePrefix = "//errhandle:"
+
+// Directive kinds understood by errhandle
+const (
+	// directiveWrapped on a function declaration marks its returned errors as already carrying a stack
+	directiveWrapped = "wrapped"
+	// directiveIgnore suppresses the diagnostics of a single return statement, reason="..." is required
+	directiveIgnore = "ignore"
+	// directiveNilNil allows `return nil, nil`, on a return statement or a function declaration
+	directiveNilNil = "nilnil"
+)
+
+var reasonPattern = regexp.MustCompile(`reason=("(?:[^"\\]|\\.)*")`)
+
+// wrappedFact is exported for functions annotated with //errhandle:wrapped,
+// so that callers in other packages trust their errors as well.
+type wrappedFact struct{}
+
+func (*wrappedFact) AFact() {}
+
+func (*wrappedFact) String() string { return "wrapped" }
+
+type directive struct {
+	kind   string
+	reason string
+	pos    token.Pos
+	target ast.Node // *ast.FuncDecl or *ast.ReturnStmt the directive applies to
+	used   bool
+}
+
+// directives holds the //errhandle: directives of a package
+type directives struct {
+	list []*directive
+}
+
+// collectDirectives parses the directives of every file, exports wrappedFact for
+// annotated functions and reports malformed or misplaced directives.
+func collectDirectives(pass *analysis.Pass, inspect *inspector.Inspector) *directives {
+	dirs := &directives{}
+
+	var comments []*ast.Comment
+	for _, file := range pass.Files {
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if strings.HasPrefix(c.Text, directivePrefix) {
+					comments = append(comments, c)
+				}
+			}
+		}
+	}
+	if len(comments) == 0 {
+		return dirs
+	}
+
+	// Directives in function doc comments target the function
+	docTargets := make(map[*ast.Comment]*ast.FuncDecl)
+	// Other directives target a return statement on the same or the next line
+	type fileLine struct {
+		file *token.File
+		line int
+	}
+	lineOf := func(pos token.Pos) fileLine {
+		file := pass.Fset.File(pos)
+		return fileLine{file: file, line: file.Line(pos)}
+	}
+	returnsByLine := make(map[fileLine]*ast.ReturnStmt)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.ReturnStmt)(nil),
+	}
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if node.Doc != nil {
+				for _, c := range node.Doc.List {
+					docTargets[c] = node
+				}
+			}
+		case *ast.ReturnStmt:
+			key := lineOf(node.Pos())
+			if _, exists := returnsByLine[key]; !exists {
+				returnsByLine[key] = node
+			}
+		}
+	})
+
+	for _, c := range comments {
+		kind, args, _ := strings.Cut(strings.TrimPrefix(c.Text, directivePrefix), " ")
+		d := &directive{kind: kind, pos: c.Pos()}
+
+		switch kind {
+		case directiveWrapped:
+			decl := docTargets[c]
+			if decl == nil {
+				reportDirective(pass, d, "//errhandle:wrapped must be placed in the doc comment of a function declaration")
+				continue
+			}
+			fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+			if !ok || !returnsError(fn) {
+				reportDirective(pass, d, fmt.Sprintf("//errhandle:wrapped on %s, which does not return an error", decl.Name.Name))
+				continue
+			}
+			pass.ExportObjectFact(fn, new(wrappedFact))
+			// Applies to callers, never reported as unused but as stale, see checkFunction
+			d.target, d.used = decl, true
+		case directiveIgnore:
+			match := reasonPattern.FindStringSubmatch(args)
+			if match != nil {
+				d.reason, _ = strconv.Unquote(match[1])
+			}
+			if strings.TrimSpace(d.reason) == "" {
+				reportDirective(pass, d, `//errhandle:ignore requires a non-empty reason="..."`)
+				continue
+			}
+		case directiveNilNil:
+			if decl := docTargets[c]; decl != nil {
+				d.target = decl
+			}
+		default:
+			reportDirective(pass, d, fmt.Sprintf("unknown directive //errhandle:%s", kind))
+			continue
+		}
+
+		if d.target == nil {
+			key := lineOf(c.Pos())
+			if ret := returnsByLine[key]; ret != nil {
+				d.target = ret
+			} else if ret := returnsByLine[fileLine{file: key.file, line: key.line + 1}]; ret != nil {
+				d.target = ret
+			} else {
+				reportDirective(pass, d, fmt.Sprintf("//errhandle:%s must be placed on or above a return statement", kind))
+				continue
+			}
+		}
+		dirs.list = append(dirs.list, d)
+	}
+	return dirs
+}
+
+// allows reports whether a directive of kind targets node, marking it as used
+func (dirs *directives) allows(kind string, node ast.Node) bool {
+	for _, d := range dirs.list {
+		if d.kind == kind && d.target == node {
+			d.used = true
+			return true
+		}
+	}
+	return false
+}
+
+// wrapped returns the //errhandle:wrapped directive of decl, if any
+func (dirs *directives) wrapped(decl *ast.FuncDecl) *directive {
+	for _, d := range dirs.list {
+		if d.kind == directiveWrapped && d.target == decl {
+			return d
+		}
+	}
+	return nil
+}
+
+// suppresses reports whether diag lies within a return statement targeted by an
+// //errhandle:ignore directive, marking the directive as used
+func (dirs *directives) suppresses(diag analysis.Diagnostic) bool {
+	for _, d := range dirs.list {
+		if d.kind == directiveIgnore && d.target.Pos() <= diag.Pos && diag.Pos < d.target.End() {
+			d.used = true
+			return true
+		}
+	}
+	return false
+}
+
+// reportUnused reports directives that did not suppress anything.
+// Directives of rules disabled at their position are not reported.
+func (dirs *directives) reportUnused(pass *analysis.Pass, enabled func(kind string, pos token.Pos) bool) {
+	for _, d := range dirs.list {
+		if !d.used && enabled(d.kind, d.pos) {
+			reportDirective(pass, d, fmt.Sprintf("unused //errhandle:%s directive", d.kind))
+		}
+	}
+}
+
+func reportDirective(pass *analysis.Pass, d *directive, message string) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      d.pos,
+		Category: "errhandle",
+		Message:  message,
+	})
+}
+
+// returnsError reports whether any result of fn implements error
+func returnsError(fn *types.Func) bool {
+	results := fn.Type().(*types.Signature).Results()
+	for i := 0; i < results.Len(); i++ {
+		if types.Implements(results.At(i).Type(), errorInterface) {
+			return true
+		}
+	}
+	return false
+}
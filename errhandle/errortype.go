@@ -0,0 +1,97 @@
+package errhandle
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkErrorTypes reports custom error types declared in the current package
+// that are returned as error without implementing all of ErrorTypeMethods.
+// Violations are reported once per type, at its declaration.
+func (l *Linter) checkErrorTypes(pass *analysis.Pass, site returnSite, reported map[*types.TypeName]bool) {
+	results := site.sig.Results()
+	if len(site.ret.Results) != results.Len() {
+		return // bare return or multi-value call
+	}
+	for i, result := range site.ret.Results {
+		// Only values returned through an error interface slot are checked
+		slot := results.At(i).Type()
+		if !types.IsInterface(slot) || !types.Implements(slot, errorInterface) {
+			continue
+		}
+
+		t := pass.TypesInfo.TypeOf(result)
+		if t == nil {
+			continue
+		}
+		obj := localErrorTypeName(pass, t)
+		if obj == nil || reported[obj] {
+			continue
+		}
+
+		if missing := l.missingErrorTypeMethods(pass, t); len(missing) > 0 {
+			reported[obj] = true
+			pass.Report(analysis.Diagnostic{
+				Pos:      obj.Pos(),
+				Category: "errhandle",
+				Message:  fmt.Sprintf("error type %s is returned as error and must implement %s", obj.Name(), strings.Join(missing, ", ")),
+			})
+		}
+	}
+}
+
+// localErrorTypeName returns the declaration of t (or of *t) if it is a
+// non-interface named type declared in the package being analyzed.
+func localErrorTypeName(pass *analysis.Pass, t types.Type) *types.TypeName {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || types.IsInterface(named) {
+		return nil
+	}
+	if named.Obj().Pkg() != pass.Pkg {
+		return nil
+	}
+	return named.Obj()
+}
+
+// errorTypeSignatures are the signatures of the well-known error methods, required when
+// ErrorTypeMethods lists them by name only
+func (l *Linter) errorTypeSignatures() map[string]string {
+	return map[string]string{
+		"Unwrap":     "func() error",
+		"Cause":      "func() error",
+		"StackTrace": "func() " + l.wrapperPackage() + ".StackTrace",
+	}
+}
+
+// missingErrorTypeMethods returns the configured methods that are not in the method set
+// of t, or that are with another signature
+func (l *Linter) missingErrorTypeMethods(pass *analysis.Pass, t types.Type) []string {
+	known := l.errorTypeSignatures()
+	var missing []string
+	for _, method := range l.settings.ErrorTypeMethods {
+		// Either a name, or a name with its signature like "Code() int"
+		name, want := method, ""
+		if i := strings.Index(method, "("); i >= 0 {
+			name, want = method[:i], "func"+method[i:]
+		} else {
+			want = known[name]
+		}
+
+		obj, _, _ := types.LookupFieldOrMethod(t, false, pass.Pkg, name)
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			missing = append(missing, method)
+			continue
+		}
+		if want != "" && signatureString(fn.Type().(*types.Signature)) != want {
+			missing = append(missing, name+strings.TrimPrefix(want, "func"))
+		}
+	}
+	return missing
+}
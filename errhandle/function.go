@@ -0,0 +1,348 @@
+package errhandle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// assignment is a value assigned to a variable
+type assignment struct {
+	pos      token.Pos // position of the assigning statement
+	rhs      ast.Expr  // assigned expression, the call itself for `a, err := f()`
+	deferred bool      // whether it happens inside a deferred function literal
+}
+
+// deferredAssignment is an assignment inside a deferred function literal
+type deferredAssignment struct {
+	obj types.Object
+	assignment
+}
+
+// returnSite is a return statement together with the signature it returns from
+type returnSite struct {
+	ret *ast.ReturnStmt
+	sig *types.Signature
+	own bool // returns from the function itself rather than from a nested function literal
+}
+
+// funcIndex is built in a single pass over a function declaration, or a package-level
+// function literal, including its nested function literals.
+type funcIndex struct {
+	root       ast.Node      // *ast.FuncDecl or *ast.FuncLit
+	decl       *ast.FuncDecl // nil for function literals
+	sigs       []*types.Signature
+	deferDepth int
+
+	namedErrors map[types.Object]bool
+	assigns     map[types.Object][]assignment // def-use index in source order
+	deferred    []deferredAssignment          // assignments in deferred function literals in source order
+	returns     []returnSite
+}
+
+func newFuncIndex(root ast.Node, sig *types.Signature) *funcIndex {
+	fn := &funcIndex{
+		root:        root,
+		sigs:        []*types.Signature{sig},
+		namedErrors: make(map[types.Object]bool),
+		assigns:     make(map[types.Object][]assignment),
+	}
+	fn.decl, _ = root.(*ast.FuncDecl)
+
+	// Named error results are checked where they are modified in defer statements
+	results := sig.Results()
+	for i := 0; i < results.Len(); i++ {
+		if v := results.At(i); v.Name() != "" && types.Implements(v.Type(), errorInterface) {
+			fn.namedErrors[v] = true
+		}
+	}
+	return fn
+}
+
+// record adds the assignment of rhs to lhs at pos to the def-use index
+func (fn *funcIndex) record(pass *analysis.Pass, lhs []ast.Expr, rhs []ast.Expr, pos token.Pos) {
+	for i, expr := range lhs {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			continue // fields, index expressions, etc. are not tracked
+		}
+		obj := pass.TypesInfo.ObjectOf(ident)
+		if obj == nil {
+			continue // blank identifier
+		}
+
+		a := assignment{pos: pos, deferred: fn.deferDepth > 0}
+		if len(rhs) == 1 {
+			// Single expression on right, possibly multiple variables on left (like _, err := someFunc())
+			a.rhs = rhs[0]
+		} else if i < len(rhs) {
+			a.rhs = rhs[i]
+		}
+
+		fn.assigns[obj] = append(fn.assigns[obj], a)
+		if a.deferred {
+			fn.deferred = append(fn.deferred, deferredAssignment{obj: obj, assignment: a})
+		}
+	}
+}
+
+// latest returns the last assignment to obj before pos
+func (fn *funcIndex) latest(obj types.Object, pos token.Pos) (assignment, bool) {
+	list := fn.assigns[obj]
+	i := sort.Search(len(list), func(i int) bool { return list[i].pos >= pos })
+	if i == 0 {
+		return assignment{}, false
+	}
+	return list[i-1], true
+}
+
+// checkFunctions indexes every function in a single traversal and checks it once
+// the traversal leaves the function.
+func (l *Linter) checkFunctions(pass *analysis.Pass, inspect *inspector.Inspector, dirs *directives) *Result {
+	result := &Result{}
+	reportedTypes := make(map[*types.TypeName]bool)
+	servers := l.grpcServers(pass)
+
+	var fn *funcIndex
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.ReturnStmt)(nil),
+	}
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if !push {
+				result.Functions = append(result.Functions, l.checkFunction(pass, fn, dirs, reportedTypes, servers))
+				fn = nil
+				return true
+			}
+			obj, ok := pass.TypesInfo.Defs[node.Name].(*types.Func)
+			if !ok || node.Body == nil {
+				return false
+			}
+			fn = newFuncIndex(node, obj.Type().(*types.Signature))
+
+		case *ast.FuncLit:
+			sig, ok := pass.TypesInfo.TypeOf(node).(*types.Signature)
+			if !ok {
+				return false
+			}
+			switch {
+			case push && fn == nil:
+				fn = newFuncIndex(node, sig) // package-level function literal
+			case push:
+				fn.sigs = append(fn.sigs, sig)
+				if isDeferredFuncLit(stack) {
+					fn.deferDepth++
+				}
+			case fn.root == node:
+				result.Functions = append(result.Functions, l.checkFunction(pass, fn, dirs, reportedTypes, servers))
+				fn = nil
+			default:
+				fn.sigs = fn.sigs[:len(fn.sigs)-1]
+				if isDeferredFuncLit(stack) {
+					fn.deferDepth--
+				}
+			}
+
+		case *ast.AssignStmt:
+			if push && fn != nil {
+				fn.record(pass, node.Lhs, node.Rhs, node.Pos())
+			}
+
+		case *ast.ValueSpec:
+			if push && fn != nil {
+				names := make([]ast.Expr, len(node.Names))
+				for i, name := range node.Names {
+					names[i] = name
+				}
+				fn.record(pass, names, node.Values, node.Pos())
+			}
+
+		case *ast.ReturnStmt:
+			if push && fn != nil {
+				fn.returns = append(fn.returns, returnSite{
+					ret: node,
+					sig: fn.sigs[len(fn.sigs)-1],
+					own: len(fn.sigs) == 1,
+				})
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// isDeferredFuncLit reports whether the function literal on top of stack is called by a defer statement
+func isDeferredFuncLit(stack []ast.Node) bool {
+	if len(stack) < 3 {
+		return false
+	}
+	lit := stack[len(stack)-1]
+	call, ok := stack[len(stack)-2].(*ast.CallExpr)
+	if !ok || call.Fun != lit {
+		return false
+	}
+	deferStmt, ok := stack[len(stack)-3].(*ast.DeferStmt)
+	return ok && deferStmt.Call == call
+}
+
+// checkFunction runs every return-based rule on an indexed function and returns
+// the provenance of its returned errors
+func (l *Linter) checkFunction(pass *analysis.Pass, fn *funcIndex, dirs *directives, reportedTypes map[*types.TypeName]bool, servers []*types.Interface) FunctionResult {
+	// Local error variables that are returned
+	returned := make(map[types.Object]bool)
+	for _, site := range fn.returns {
+		for _, result := range site.ret.Results {
+			if ident, ok := result.(*ast.Ident); ok && l.isErrorType(pass, ident) {
+				if obj := pass.TypesInfo.ObjectOf(ident); obj != nil && !fn.namedErrors[obj] {
+					returned[obj] = true
+				}
+			}
+		}
+	}
+
+	// Returned variables that a defer statement wraps properly are not reported at the return
+	wrappedInDefer := make(map[types.Object]bool)
+	for _, d := range fn.deferred {
+		if !returned[d.obj] && !fn.namedErrors[d.obj] {
+			continue
+		}
+		if call, ok := d.rhs.(*ast.CallExpr); ok && !l.shouldReportCallWithTypeInfo(pass, call) {
+			wrappedInDefer[d.obj] = true
+		}
+	}
+
+	pos := fn.root.Pos()
+	boundary := l.boundaryKind(pass, fn.decl, servers)
+	wrap := l.ruleEnabled(pass, ruleWrap, pos)
+	translate := boundary != "" && l.ruleEnabled(pass, ruleBoundary, pos)
+	if translate && boundary == boundaryHTTP && fn.decl != nil && fn.decl.Type.Results == nil {
+		l.checkHandlerWrites(pass, fn)
+	}
+	nilNil := l.settings.NilNil.Enabled && l.nilNilInScope(pass.Pkg.Path()) && l.ruleEnabled(pass, ruleNilNil, pos)
+	errorTypes := len(l.settings.ErrorTypeMethods) > 0 && l.ruleEnabled(pass, ruleErrorType, pos)
+	for _, site := range fn.returns {
+		// Boundary functions translate their errors rather than wrap them
+		if boundary != "" && site.own {
+			if translate {
+				l.checkBoundary(pass, fn, site, boundary)
+			}
+		} else if wrap {
+			l.checkReturn(pass, fn, site.ret, wrappedInDefer)
+		}
+		if nilNil {
+			l.checkNilNil(pass, fn, site, dirs)
+		}
+		if errorTypes {
+			l.checkErrorTypes(pass, site, reportedTypes)
+		}
+	}
+
+	result, ownForeign := l.functionResult(pass, fn, wrappedInDefer)
+	if d := dirs.wrapped(fn.decl); d != nil && ownForeign && wrap {
+		reportDirective(pass, d, fmt.Sprintf("stale //errhandle:wrapped directive, %s returns errors that are not wrapped", fn.decl.Name.Name))
+	}
+	if boundary != "" || !l.ruleEnabled(pass, ruleDefer, pos) {
+		return result
+	}
+
+	// Check for error modifications in defer statements
+	for _, d := range fn.deferred {
+		if !fn.namedErrors[d.obj] && !returned[d.obj] {
+			continue
+		}
+		if call, ok := d.rhs.(*ast.CallExpr); ok && l.shouldReportCallWithTypeInfo(pass, call) {
+			pass.Report(analysis.Diagnostic{
+				Pos:      call.Pos(),
+				Category: "errhandle",
+				Message:  fmt.Sprintf("error in defer should use %s", l.wrapperPackage()),
+			})
+		}
+	}
+	return result
+}
+
+func (l *Linter) checkReturn(pass *analysis.Pass, fn *funcIndex, ret *ast.ReturnStmt, wrappedInDefer map[types.Object]bool) {
+	// Check for direct function call returns like "return strconv.ParseInt(...)"
+	// that return multiple values including an error
+	if len(ret.Results) == 1 {
+		if callExpr, ok := ret.Results[0].(*ast.CallExpr); ok {
+			if tuple, ok := pass.TypesInfo.TypeOf(callExpr).(*types.Tuple); ok {
+				for i := 0; i < tuple.Len(); i++ {
+					if types.Implements(tuple.At(i).Type(), errorInterface) {
+						if l.shouldReportCallWithTypeInfo(pass, callExpr) {
+							l.reportUnwrapped(pass, callExpr)
+						}
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// Process individual return values
+	for _, result := range ret.Results {
+		if !l.isErrorType(pass, result) {
+			continue
+		}
+
+		// Skip return values handled in defer statements
+		if ident, ok := result.(*ast.Ident); ok {
+			if obj := pass.TypesInfo.ObjectOf(ident); fn.namedErrors[obj] || wrappedInDefer[obj] {
+				continue
+			}
+		}
+
+		if l.shouldReportExpr(pass, fn, result, ret.Pos()) {
+			l.reportUnwrapped(pass, result)
+		}
+	}
+}
+
+// shouldReportExpr reports whether the error value of expr, as of pos, is not wrapped
+func (l *Linter) shouldReportExpr(pass *analysis.Pass, fn *funcIndex, expr ast.Expr, pos token.Pos) bool {
+	p, ok := l.classifyExpr(pass, fn, expr, pos)
+	return ok && p == ProvenanceForeign
+}
+
+// classifyExpr returns the provenance of the error value of expr as of pos, ok is false for nil.
+// Variables are resolved through the def-use index of fn.
+func (l *Linter) classifyExpr(pass *analysis.Pass, fn *funcIndex, expr ast.Expr, pos token.Pos) (p Provenance, ok bool) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		return l.classifyCall(pass, e), true
+	case *ast.Ident:
+		if isNil(pass, e) {
+			return 0, false
+		}
+		return l.classifyVar(pass, fn, pass.TypesInfo.ObjectOf(e), pos)
+	}
+	return ProvenanceForeign, true
+}
+
+// classifyVar returns the provenance of the value of obj as of pos
+func (l *Linter) classifyVar(pass *analysis.Pass, fn *funcIndex, obj types.Object, pos token.Pos) (Provenance, bool) {
+	a, ok := fn.latest(obj, pos)
+	if !ok {
+		return ProvenanceForeign, true // parameters and variables without a traceable value
+	}
+	// Trace the source as of that assignment, so that `a = b; b = a` terminates
+	return l.classifyExpr(pass, fn, a.rhs, a.pos)
+}
+
+func (l *Linter) reportUnwrapped(pass *analysis.Pass, expr ast.Expr) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      expr.Pos(),
+		Category: "errhandle",
+		Message:  fmt.Sprintf("error should use %s", l.wrapperPackage()),
+	})
+}
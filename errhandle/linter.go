@@ -3,13 +3,19 @@ package errhandle
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 
-	"go/token"
-
 	"github.com/golangci/plugin-module-register/register"
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 func init() {
@@ -17,8 +23,66 @@ func init() {
 }
 
 type Settings struct {
-	ProjectPath string   `json:"project-path"` // Root project path to identify internal code
-	Whitelist   []string `json:"whitelist"`    // Package paths to exclude from error reporting
+	ProjectPath    string   `json:"project-path"`    // Root project path to identify internal code
+	Whitelist      []string `json:"whitelist"`       // Package paths to exclude from error reporting
+	WrapperPackage string   `json:"wrapper-package"` // Error wrapping library, defaults to github.com/pkg/errors
+
+	// ErrorTypeMethods lists the methods (e.g. "StackTrace", "Unwrap", "Cause", "Code")
+	// that custom error types declared in the analyzed package must implement
+	// when their values are returned as error. Methods may be given with their
+	// signature, with full package paths, e.g. "Code() int"; StackTrace, Unwrap
+	// and Cause otherwise require their usual signatures.
+	ErrorTypeMethods []string `json:"error-type-methods"`
+
+	// CheckComparisons reports == / != comparisons, value switches and type assertions on error values,
+	// which stop matching once the errors are wrapped.
+	CheckComparisons bool `json:"check-comparisons"`
+
+	// NilNil configures the checks on functions returning (*T, error)
+	NilNil NilNilSettings `json:"nil-nil"`
+
+	// Boundary configures the handler layer, where errors must be translated instead of wrapped
+	Boundary BoundarySettings `json:"boundary"`
+
+	SkipGenerated bool     `json:"skip-generated"` // Skip files with the standard "Code generated ... DO NOT EDIT." header
+	SkipTests     bool     `json:"skip-tests"`     // Skip _test.go files
+	ExcludeFiles  []string `json:"exclude-files"`  // Filename glob patterns to skip, e.g. "*.pb.go" or "ent/*.go"
+
+	// TestRules, when set, limits the rules applied to _test.go files, e.g. ["comparison"].
+	// An empty list disables every rule in tests while still checking directives.
+	TestRules []string `json:"test-rules"`
+}
+
+// Rule names, used by TestRules
+const (
+	ruleWrap       = "wrap"       // returned errors must be wrapped
+	ruleDefer      = "defer"      // errors assigned in defer statements must be wrapped
+	ruleErrorType  = "error-type" // custom error types must implement ErrorTypeMethods
+	ruleComparison = "comparison" // errors must not be compared with ==, switches or type assertions
+	ruleNilNil     = "nil-nil"    // functions returning (*T, error) must not return nil, nil
+	ruleBoundary   = "boundary"   // boundary functions must return translated errors
+)
+
+var rules = []string{ruleWrap, ruleDefer, ruleErrorType, ruleComparison, ruleNilNil, ruleBoundary}
+
+// NilNilSettings configures the opt-in checks on functions returning (*T, error):
+// `return nil, nil` must be annotated with //errhandle:nilnil, and a non-nil value
+// must not be returned together with an error from a foreign call.
+type NilNilSettings struct {
+	Enabled  bool     `json:"enabled"`
+	Packages []string `json:"packages"` // Package paths the checks apply to, defaults to ProjectPath
+}
+
+// BoundarySettings configures the handler layer: errors returned from boundary
+// functions must be produced by a translator, e.g. status.Error for gRPC, and are
+// exempt from the wrap rule so that stacks do not leak into responses.
+type BoundarySettings struct {
+	Kinds       []string `json:"kinds"`       // "grpc" for generated *Server interface methods, "http" for http.HandlerFunc, with or without an error result
+	Translators []string `json:"translators"` // Fully qualified functions, e.g. "google.golang.org/grpc/status.Error"
+
+	// Signatures lists further boundary function signatures, without parameter names and
+	// with full package paths, e.g. "func(github.com/labstack/echo/v4.Context) error"
+	Signatures []string `json:"signatures"`
 }
 
 type Linter struct {
@@ -31,6 +95,25 @@ func New(settings any) (register.LinterPlugin, error) {
 		return nil, err
 	}
 
+	for _, pattern := range s.ExcludeFiles {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude-files pattern %q: %w", pattern, err)
+		}
+	}
+	for _, kind := range s.Boundary.Kinds {
+		if !slices.Contains(boundaryKinds, kind) {
+			return nil, fmt.Errorf("unknown boundary kind %q, must be one of %s", kind, strings.Join(boundaryKinds, ", "))
+		}
+	}
+	if (len(s.Boundary.Kinds) > 0 || len(s.Boundary.Signatures) > 0) && len(s.Boundary.Translators) == 0 {
+		return nil, fmt.Errorf("boundary requires at least one translator")
+	}
+	for _, rule := range s.TestRules {
+		if !slices.Contains(rules, rule) {
+			return nil, fmt.Errorf("unknown test rule %q, must be one of %s", rule, strings.Join(rules, ", "))
+		}
+	}
+
 	return &Linter{settings: s}, nil
 }
 
@@ -38,8 +121,13 @@ func (l *Linter) BuildAnalyzers() ([]*analysis.Analyzer, error) {
 	return []*analysis.Analyzer{
 		{
 			Name: "errhandle",
-			Doc:  "Check if return statements use github.com/pkg/errors",
+			Doc:  "Check if returned errors are wrapped with the configured wrapper package, github.com/pkg/errors by default",
 			Run:  l.run,
+
+			ResultType: reflect.TypeOf((*Result)(nil)),
+
+			Requires:  []*analysis.Analyzer{inspect.Analyzer},
+			FactTypes: []analysis.Fact{new(wrappedFact)},
 		},
 	}, nil
 }
@@ -50,6 +138,14 @@ func (l *Linter) GetLoadMode() string {
 
 const pkgErrorsPath = "github.com/pkg/errors"
 
+// wrapperPackage returns the configured error wrapping library
+func (l *Linter) wrapperPackage() string {
+	if l.settings.WrapperPackage != "" {
+		return l.settings.WrapperPackage
+	}
+	return pkgErrorsPath
+}
+
 // errorInterface is the error interface type for type checking
 var errorInterface *types.Interface
 
@@ -62,136 +158,105 @@ func init() {
 }
 
 func (l *Linter) run(pass *analysis.Pass) (any, error) {
-	for _, file := range pass.Files {
-		// Build import map for this file
-		importMap := make(map[string]string)
-		dotImports := make(map[string]bool)
-
-		for _, imp := range file.Imports {
-			path := strings.Trim(imp.Path.Value, "\"")
-			if imp.Name != nil {
-				if imp.Name.Name == "." {
-					dotImports[path] = true
-				} else {
-					importMap[imp.Name.Name] = path
-				}
-			} else {
-				parts := strings.Split(path, "/")
-				pkgName := parts[len(parts)-1]
-				importMap[pkgName] = path
-			}
-		}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
-		// Check each function separately
-		ast.Inspect(file, func(n ast.Node) bool {
-			if funcDecl, ok := n.(*ast.FuncDecl); ok && funcDecl.Body != nil {
-				// Process this function
-				l.checkFunction(pass, funcDecl, importMap, dotImports)
-				return false // Don't traverse into this function's body again
-			}
+	// Leave out generated, test and excluded files as configured
+	files := l.includedFiles(pass)
+	if len(files) != len(pass.Files) {
+		inspect = inspector.New(files)
+	}
+	included := make(map[*token.File]bool, len(files))
+	for _, file := range files {
+		included[pass.Fset.File(file.Pos())] = true
+	}
+
+	report := pass.Report
+	filtered := *pass
+	filtered.Files = files
+	pass = &filtered
+
+	dirs := collectDirectives(pass, inspect)
+	defer dirs.reportUnused(pass, func(kind string, pos token.Pos) bool {
+		switch kind {
+		case directiveIgnore:
 			return true
-		})
+		case directiveNilNil:
+			return l.settings.NilNil.Enabled && l.nilNilInScope(pass.Pkg.Path()) && l.ruleEnabled(pass, ruleNilNil, pos)
+		}
+		return false
+	})
+
+	// Drop diagnostics in skipped files, e.g. on error types declared there,
+	// and within return statements marked with //errhandle:ignore
+	filtered.Report = func(diag analysis.Diagnostic) {
+		if file := pass.Fset.File(diag.Pos); file != nil && !included[file] {
+			return
+		}
+		if !dirs.suppresses(diag) {
+			report(diag)
+		}
 	}
-	return nil, nil
+
+	result := l.checkFunctions(pass, inspect, dirs)
+	l.checkComparisons(pass, inspect)
+	return result, nil
 }
 
-func (l *Linter) checkFunction(pass *analysis.Pass, funcDecl *ast.FuncDecl, importMap map[string]string, _ map[string]bool) {
-	// Get named error return values for checking defer statements
-	namedErrorReturns := l.getNamedErrorReturns(pass, funcDecl)
-
-	// Find all local error variables that are returned and modified in defer
-	localErrorVars := make(map[string]bool)
-
-	// First pass: identify all local error variables that are returned
-	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
-		if ret, ok := n.(*ast.ReturnStmt); ok {
-			for _, result := range ret.Results {
-				if l.isErrorType(pass, result) {
-					if ident, ok := result.(*ast.Ident); ok {
-						// This is a local variable being returned
-						if !namedErrorReturns[ident.Name] {
-							localErrorVars[ident.Name] = true
-						}
-					}
-				}
-			}
+// includedFiles returns the files of the package that are not skipped by the settings
+func (l *Linter) includedFiles(pass *analysis.Pass) []*ast.File {
+	files := make([]*ast.File, 0, len(pass.Files))
+	for _, file := range pass.Files {
+		filename := pass.Fset.File(file.Pos()).Name()
+		switch {
+		case l.settings.SkipGenerated && ast.IsGenerated(file):
+		case l.settings.SkipTests && isTestFile(filename):
+		case l.isExcludedFile(filename):
+		default:
+			files = append(files, file)
 		}
-		return true
-	})
+	}
+	return files
+}
 
-	// Check if these local error vars are modified in defer
-	modifiedErrorVars := l.findErrorVarsModifiedInDefer(pass, funcDecl, importMap, localErrorVars)
-
-	// Second pass: check return statements
-	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
-		if ret, ok := n.(*ast.ReturnStmt); ok {
-			// Check for direct function call returns like "return strconv.ParseInt(...)"
-			// that return multiple values including an error
-			if len(ret.Results) == 1 {
-				if callExpr, ok := ret.Results[0].(*ast.CallExpr); ok {
-					// Check if this function returns an error type
-					if t := pass.TypesInfo.TypeOf(callExpr); t != nil {
-						if tuple, ok := t.(*types.Tuple); ok {
-							// Check if any of the return values is an error
-							for i := 0; i < tuple.Len(); i++ {
-								varType := tuple.At(i).Type()
-								if types.Implements(varType, errorInterface) {
-									// This is a function call that returns an error
-									if l.shouldReportCallWithTypeInfo(pass, callExpr, importMap) {
-										pass.Report(analysis.Diagnostic{
-											Pos:      callExpr.Pos(),
-											Category: "errhandle",
-											Message:  fmt.Sprintf("error should use %s", pkgErrorsPath),
-										})
-									}
-									break
-								}
-							}
-						}
-					}
-				}
+// isExcludedFile reports whether filename matches one of the exclude-files patterns.
+// Patterns without a slash match the base name, others match the trailing path elements.
+func (l *Linter) isExcludedFile(filename string) bool {
+	filename = filepath.ToSlash(filename)
+	for _, pattern := range l.settings.ExcludeFiles {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(filename)); ok {
+				return true
 			}
-
-			// Process individual return values
-			for _, result := range ret.Results {
-				// Check if this return value is an error type
-				if l.isErrorType(pass, result) {
-					// Handle direct function calls in return statements
-					if callExpr, ok := result.(*ast.CallExpr); ok {
-						// Direct function call return
-						if l.shouldReportCallWithTypeInfo(pass, callExpr, importMap) {
-							pass.Report(analysis.Diagnostic{
-								Pos:      callExpr.Pos(),
-								Category: "errhandle",
-								Message:  fmt.Sprintf("error should use %s", pkgErrorsPath),
-							})
-						}
-						continue
-					}
-
-					// Skip checking return values that are modified in defer statements
-					if ident, ok := result.(*ast.Ident); ok {
-						if namedErrorReturns[ident.Name] || modifiedErrorVars[ident.Name] {
-							// This error return value is handled in defer, so skip it here
-							continue
-						}
-					}
-
-					if l.shouldReportWithTypeInfo(pass, result, funcDecl.Body, importMap, ret.Pos()) {
-						pass.Report(analysis.Diagnostic{
-							Pos:      result.Pos(),
-							Category: "errhandle",
-							Message:  fmt.Sprintf("error should use %s", pkgErrorsPath),
-						})
-					}
-				}
+			continue
+		}
+		for name := filename; ; {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
 			}
+			i := strings.Index(name, "/")
+			if i < 0 {
+				break
+			}
+			name = name[i+1:]
 		}
+	}
+	return false
+}
+
+// ruleEnabled reports whether rule applies to the file containing pos
+func (l *Linter) ruleEnabled(pass *analysis.Pass, rule string, pos token.Pos) bool {
+	if l.settings.TestRules == nil {
 		return true
-	})
+	}
+	file := pass.Fset.File(pos)
+	if file == nil || !isTestFile(file.Name()) {
+		return true
+	}
+	return slices.Contains(l.settings.TestRules, rule)
+}
 
-	// Check for error modifications in defer statements
-	l.checkDeferErrorModifications(pass, funcDecl, importMap, namedErrorReturns, localErrorVars)
+func isTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "_test.go")
 }
 
 func (l *Linter) isErrorType(pass *analysis.Pass, expr ast.Expr) bool {
@@ -201,41 +266,38 @@ func (l *Linter) isErrorType(pass *analysis.Pass, expr ast.Expr) bool {
 	return false
 }
 
-func (l *Linter) shouldReportWithTypeInfo(pass *analysis.Pass, expr ast.Expr, funcBody *ast.BlockStmt, importMap map[string]string, returnPos token.Pos) bool {
-	switch e := expr.(type) {
-	case *ast.CallExpr:
-		return l.shouldReportCallWithTypeInfo(pass, e, importMap)
-	case *ast.Ident:
-		return l.shouldReportVarWithTypeInfo(pass, e, funcBody, importMap, returnPos)
-	}
-	return true
+func (l *Linter) shouldReportCallWithTypeInfo(pass *analysis.Pass, call *ast.CallExpr) bool {
+	return l.classifyCall(pass, call) == ProvenanceForeign
 }
 
-func (l *Linter) shouldReportCallWithTypeInfo(pass *analysis.Pass, call *ast.CallExpr, importMap map[string]string) bool {
+// classifyCall returns the provenance of the error returned by call
+func (l *Linter) classifyCall(pass *analysis.Pass, call *ast.CallExpr) Provenance {
+	// Functions annotated with //errhandle:wrapped already return stack-carrying errors
+	if fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func); ok && pass.ImportObjectFact(fn, new(wrappedFact)) {
+		return ProvenanceWrapped
+	}
+
 	if selExpr, ok := call.Fun.(*ast.SelectorExpr); ok {
-		return l.handleSelectorCall(pass, selExpr, importMap)
+		return l.handleSelectorCall(pass, selExpr)
 	}
 
 	// Handle direct function calls (could be from dot imports or same package)
 	if ident, ok := call.Fun.(*ast.Ident); ok {
-		return l.handleDirectCall(pass, ident, importMap)
+		return l.handleDirectCall(pass, ident)
 	}
 
-	return false // Can't determine, assume should ignore it
+	return ProvenanceInternal // Can't determine, assume should ignore it
 }
 
-func (l *Linter) handleSelectorCall(pass *analysis.Pass, selExpr *ast.SelectorExpr, importMap map[string]string) bool {
+func (l *Linter) handleSelectorCall(pass *analysis.Pass, selExpr *ast.SelectorExpr) Provenance {
 	if pkgIdent, ok := selExpr.X.(*ast.Ident); ok {
-		pkgName := pkgIdent.Name
-		if pkgPath, exists := importMap[pkgName]; exists {
+		if pkgName, ok := pass.TypesInfo.Uses[pkgIdent].(*types.PkgName); ok {
 			// This is a package.function() call
-			if strings.HasPrefix(pkgPath, pkgErrorsPath) {
-				return false // Don't report pkg/errors
+			pkgPath := pkgName.Imported().Path()
+			if strings.HasPrefix(pkgPath, l.wrapperPackage()) {
+				return ProvenanceWrapped // Don't report the wrapper package
 			}
-			if l.shouldIgnorePackage(pkgPath) {
-				return false // Don't report it
-			}
-			return true // Report external packages
+			return l.packageProvenance(pkgPath) // Report external packages
 		}
 	}
 
@@ -260,260 +322,43 @@ func (l *Linter) handleSelectorCall(pass *analysis.Pass, selExpr *ast.SelectorEx
 
 		// If we found the method's package, check if it's external package
 		if methodPkgPath != "" {
-			if l.shouldIgnorePackage(methodPkgPath) {
-				return false // Don't report it
-			}
-			return true // Report it
+			return l.packageProvenance(methodPkgPath)
 		}
 	}
 
-	return false // Can't determine, assume should ignore it
+	return ProvenanceInternal // Can't determine, assume should ignore it
 }
 
-func (l *Linter) handleDirectCall(pass *analysis.Pass, ident *ast.Ident, _ map[string]string) bool {
+func (l *Linter) handleDirectCall(pass *analysis.Pass, ident *ast.Ident) Provenance {
 	// Check if this function is from the same package or dot imports
 	if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
 		if obj.Pkg() != nil {
 			pkgPath := obj.Pkg().Path()
-			// Check if it's from pkgErrorsPath
-			if strings.HasPrefix(pkgPath, pkgErrorsPath) {
-				return false // Don't report pkgErrorsPath
+			// Check if it's from the wrapper package
+			if strings.HasPrefix(pkgPath, l.wrapperPackage()) {
+				return ProvenanceWrapped // Don't report the wrapper package
 			}
-			// Check if it's from an internal package
-			if l.shouldIgnorePackage(pkgPath) {
-				return false // Don't report it
-			}
-			// It's from an external package (like stdlib via dot import)
-			return true // Report external packages
+			// It's from an internal, whitelisted or external package (like stdlib via dot import)
+			return l.packageProvenance(pkgPath)
 		}
 	}
 
-	return false // Same package or can't determine - don't report it
+	return ProvenanceInternal // Same package or can't determine - don't report it
 }
 
-func (l *Linter) shouldReportVarWithTypeInfo(pass *analysis.Pass, ident *ast.Ident, funcBody *ast.BlockStmt, importMap map[string]string, returnPos token.Pos) bool {
-	varName := ident.Name
-	shouldReport := true
-	var lastAssignPos token.Pos
-
-	// Only search within the current function body, and only consider assignments before the return statement
-	ast.Inspect(funcBody, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.AssignStmt:
-			// Skip assignments that come after the return statement
-			if node.Pos() >= returnPos {
-				return true
-			}
-
-			for i, lhs := range node.Lhs {
-				if lhsIdent, ok := lhs.(*ast.Ident); ok && lhsIdent.Name == varName {
-					// Only consider this assignment if it's the latest one before the return
-					if node.Pos() > lastAssignPos {
-						lastAssignPos = node.Pos()
-
-						// For multiple assignment, we need to check if there's a single call on the right
-						// that returns multiple values (like _, err := someFunc())
-						if len(node.Rhs) == 1 {
-							// Single expression on right, multiple variables on left
-							if callExpr, ok := node.Rhs[0].(*ast.CallExpr); ok {
-								shouldReport = l.shouldReportCallWithTypeInfo(pass, callExpr, importMap)
-								return true
-							}
-							if rhsIdent, ok := node.Rhs[0].(*ast.Ident); ok {
-								shouldReport = l.shouldReportVarWithTypeInfo(pass, rhsIdent, funcBody, importMap, returnPos)
-								return true
-							}
-						} else if i < len(node.Rhs) {
-							// Normal assignment, one-to-one mapping
-							if callExpr, ok := node.Rhs[i].(*ast.CallExpr); ok {
-								shouldReport = l.shouldReportCallWithTypeInfo(pass, callExpr, importMap)
-								return true
-							}
-							if rhsIdent, ok := node.Rhs[i].(*ast.Ident); ok {
-								shouldReport = l.shouldReportVarWithTypeInfo(pass, rhsIdent, funcBody, importMap, returnPos)
-								return true
-							}
-						}
-					}
-				}
-			}
-		case *ast.GenDecl:
-			// Skip declarations that come after the return statement
-			if node.Pos() >= returnPos {
-				return true
-			}
-
-			for _, spec := range node.Specs {
-				if valueSpec, ok := spec.(*ast.ValueSpec); ok {
-					for i, name := range valueSpec.Names {
-						if name.Name == varName && i < len(valueSpec.Values) {
-							// Only consider this declaration if it's the latest one before the return
-							if node.Pos() > lastAssignPos {
-								lastAssignPos = node.Pos()
-
-								if callExpr, ok := valueSpec.Values[i].(*ast.CallExpr); ok {
-									shouldReport = l.shouldReportCallWithTypeInfo(pass, callExpr, importMap)
-									return true
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-		return true
-	})
-
-	return shouldReport
-}
-
-func (l *Linter) shouldIgnorePackage(pkgPath string) bool {
+// packageProvenance classifies errors returned by functions of a package
+func (l *Linter) packageProvenance(pkgPath string) Provenance {
 	// Check if it's an internal package
 	if l.settings.ProjectPath != "" && strings.HasPrefix(pkgPath, l.settings.ProjectPath) {
-		return true
+		return ProvenanceInternal
 	}
 
 	// Check if it's in the whitelist
 	for _, whitelistedPath := range l.settings.Whitelist {
 		if strings.HasPrefix(pkgPath, whitelistedPath) {
-			return true
+			return ProvenanceWhitelisted
 		}
 	}
 
-	return false
-}
-
-// getNamedErrorReturns identifies named error return values in a function
-func (l *Linter) getNamedErrorReturns(pass *analysis.Pass, funcDecl *ast.FuncDecl) map[string]bool {
-	namedErrorReturns := make(map[string]bool)
-	if funcDecl.Type.Results != nil {
-		for _, field := range funcDecl.Type.Results.List {
-			// Check if this field is of type error
-			if l.isErrorType(pass, field.Type) {
-				// Add all names to our map
-				for _, name := range field.Names {
-					namedErrorReturns[name.Name] = true
-				}
-			}
-		}
-	}
-	return namedErrorReturns
-}
-
-// findErrorVarsModifiedInDefer identifies local error variables that are modified in defer statements
-func (l *Linter) findErrorVarsModifiedInDefer(pass *analysis.Pass, funcDecl *ast.FuncDecl, importMap map[string]string, localErrorVars map[string]bool) map[string]bool {
-	modifiedVars := make(map[string]bool)
-
-	// If no local error vars, nothing to check
-	if len(localErrorVars) == 0 {
-		return modifiedVars
-	}
-
-	// Find all defer statements in the function body
-	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
-		if deferStmt, ok := n.(*ast.DeferStmt); ok {
-			// Check if this defer statement contains a function literal (anonymous function)
-			if funcLit, ok := deferStmt.Call.Fun.(*ast.FuncLit); ok && funcLit.Body != nil {
-				// Inspect the function body for assignments to local error vars
-				ast.Inspect(funcLit.Body, func(innerNode ast.Node) bool {
-					if assignStmt, ok := innerNode.(*ast.AssignStmt); ok {
-						for i, lhs := range assignStmt.Lhs {
-							if lhsIdent, ok := lhs.(*ast.Ident); ok {
-								// Check if this is assigning to a local error var
-								if localErrorVars[lhsIdent.Name] {
-									// We found an assignment to a local error var in a defer
-									var rhsExpr ast.Expr
-									if i < len(assignStmt.Rhs) {
-										rhsExpr = assignStmt.Rhs[i]
-									} else if len(assignStmt.Rhs) == 1 {
-										// Multiple assignment with one value on right
-										rhsExpr = assignStmt.Rhs[0]
-									}
-
-									if rhsExpr != nil {
-										// Check if this is a call expression
-										if callExpr, ok := rhsExpr.(*ast.CallExpr); ok {
-											// Check if the call is using pkg/errors
-											if !l.shouldReportCallWithTypeInfo(pass, callExpr, importMap) {
-												// This error is properly handled with pkg/errors
-												modifiedVars[lhsIdent.Name] = true
-											}
-										}
-									}
-								}
-							}
-						}
-					}
-					return true
-				})
-			}
-		}
-		return true
-	})
-
-	return modifiedVars
-}
-
-// checkDeferErrorModifications checks if error return values are modified in defer statements
-// and reports if the modifications don't use github.com/pkg/errors
-func (l *Linter) checkDeferErrorModifications(pass *analysis.Pass, funcDecl *ast.FuncDecl, importMap map[string]string, namedErrorReturns map[string]bool, localErrorVars map[string]bool) {
-	// Combine named error returns and local error vars that are returned
-	allErrorVars := make(map[string]bool)
-	for name := range namedErrorReturns {
-		allErrorVars[name] = true
-	}
-	for name := range localErrorVars {
-		allErrorVars[name] = true
-	}
-
-	// If no error vars to check, return
-	if len(allErrorVars) == 0 {
-		return
-	}
-
-	// Find all defer statements in the function body
-	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
-		if deferStmt, ok := n.(*ast.DeferStmt); ok {
-			// Check if this defer statement contains a function literal (anonymous function)
-			if funcLit, ok := deferStmt.Call.Fun.(*ast.FuncLit); ok && funcLit.Body != nil {
-				// Inspect the function body for assignments to error vars
-				ast.Inspect(funcLit.Body, func(innerNode ast.Node) bool {
-					if assignStmt, ok := innerNode.(*ast.AssignStmt); ok {
-						for i, lhs := range assignStmt.Lhs {
-							if lhsIdent, ok := lhs.(*ast.Ident); ok {
-								// Check if this is assigning to an error var
-								if allErrorVars[lhsIdent.Name] {
-									// We found an assignment to an error var in a defer
-									var rhsExpr ast.Expr
-									if i < len(assignStmt.Rhs) {
-										rhsExpr = assignStmt.Rhs[i]
-									} else if len(assignStmt.Rhs) == 1 {
-										// Multiple assignment with one value on right
-										rhsExpr = assignStmt.Rhs[0]
-									}
-
-									if rhsExpr != nil {
-										// Check if this is a call expression
-										if callExpr, ok := rhsExpr.(*ast.CallExpr); ok {
-											// Check if the call is using pkg/errors
-											if l.shouldReportCallWithTypeInfo(pass, callExpr, importMap) {
-												// This error is not properly handled
-												pass.Report(analysis.Diagnostic{
-													Pos:      callExpr.Pos(),
-													Category: "errhandle",
-													Message:  fmt.Sprintf("error in defer should use %s", pkgErrorsPath),
-												})
-											}
-										}
-									}
-								}
-							}
-						}
-					}
-					return true
-				})
-			}
-		}
-		return true
-	})
+	return ProvenanceForeign
 }
@@ -0,0 +1,84 @@
+package errhandle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+func BenchmarkLargeFunction(b *testing.B) {
+	for _, blocks := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("blocks=%d", blocks), func(b *testing.B) {
+			pass := newBenchmarkPass(b, largeFunctionSource(blocks))
+			linter := &Linter{settings: Settings{ProjectPath: "bench"}}
+
+			for b.Loop() {
+				if _, err := linter.run(pass); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// largeFunctionSource generates a single function with the given number of
+// assign-check-return blocks, each returning a reassigned error variable.
+func largeFunctionSource(blocks int) string {
+	var sb strings.Builder
+	sb.WriteString("package bench\n\nimport (\n\t\"fmt\"\n\t\"strconv\"\n)\n\n")
+	sb.WriteString("func large(s string) (int, error) {\n\ttotal := 0\n")
+	for i := 0; i < blocks; i++ {
+		fmt.Fprintf(&sb, "\tv%d, err := strconv.Atoi(s)\n", i)
+		fmt.Fprintf(&sb, "\tif err != nil {\n\t\treturn 0, err\n\t}\n")
+		fmt.Fprintf(&sb, "\terr%d := err\n", i)
+		fmt.Fprintf(&sb, "\tif v%d < 0 {\n\t\treturn 0, err%d\n\t}\n", i, i)
+		fmt.Fprintf(&sb, "\tif v%d > 100 {\n\t\treturn 0, fmt.Errorf(\"too large: %%d\", v%d)\n\t}\n", i, i)
+		fmt.Fprintf(&sb, "\ttotal += v%d\n", i)
+	}
+	sb.WriteString("\treturn total, nil\n}\n")
+	return sb.String()
+}
+
+func newBenchmarkPass(b *testing.B, src string) *analysis.Pass {
+	b.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bench.go", src, parser.ParseComments)
+	if err != nil {
+		b.Fatal(err)
+	}
+	files := []*ast.File{file}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("bench", fset, files, info)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return &analysis.Pass{
+		Fset:      fset,
+		Files:     files,
+		Pkg:       pkg,
+		TypesInfo: info,
+		ResultOf: map[*analysis.Analyzer]any{
+			inspect.Analyzer: inspector.New(files),
+		},
+		Report:           func(analysis.Diagnostic) {},
+		ImportObjectFact: func(types.Object, analysis.Fact) bool { return false },
+		ExportObjectFact: func(types.Object, analysis.Fact) {},
+	}
+}
@@ -1,6 +1,7 @@
 package errhandle
 
 import (
+	"reflect"
 	"testing"
 
 	"golang.org/x/tools/go/analysis/analysistest"
@@ -36,3 +37,239 @@ func TestErrorHandleLinter(t *testing.T) {
 	// Run the test using analysistest with go.mod support
 	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/testpkg")
 }
+
+func TestVariableTracing(t *testing.T) {
+	settings := Settings{
+		ProjectPath: "testdata",
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	// Variables are traced to their value as of each assignment, not as of the return
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/tracing")
+}
+
+func TestErrorTypeMethods(t *testing.T) {
+	settings := Settings{
+		ProjectPath:      "testdata",
+		ErrorTypeMethods: []string{"Unwrap", "Cause"},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/errortype")
+}
+
+func TestErrorTypeSignatures(t *testing.T) {
+	settings := Settings{
+		ProjectPath:      "testdata",
+		ErrorTypeMethods: []string{"StackTrace", "Code() int"},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/errortypesig")
+}
+
+func TestErrorComparisons(t *testing.T) {
+	settings := Settings{
+		ProjectPath:      "testdata",
+		CheckComparisons: true,
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	// Also verifies the suggested fixes against the .golden files
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzers[0], "testdata/comparison")
+}
+
+func TestErrorComparisonsStdlib(t *testing.T) {
+	settings := Settings{
+		ProjectPath:      "testdata",
+		WrapperPackage:   "errors",
+		CheckComparisons: true,
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	// The standard errors package has no Cause, type assertions are fixed with errors.As
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzers[0], "testdata/comparisonstd")
+}
+
+func TestNilNil(t *testing.T) {
+	settings := Settings{
+		ProjectPath: "testdata",
+		NilNil: NilNilSettings{
+			Enabled: true,
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/nilnil")
+}
+
+func TestDirectives(t *testing.T) {
+	settings := Settings{
+		// testdata/wrappedlib is a foreign package here, trusted only through its directives
+		ProjectPath: "testdata/directives",
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/directives")
+}
+
+func TestFileFilters(t *testing.T) {
+	settings := Settings{
+		ProjectPath:      "testdata",
+		CheckComparisons: true,
+		SkipGenerated:    true,
+		ExcludeFiles:     []string{"*_mock.go", "files/ent/*.go"},
+		TestRules:        []string{ruleComparison},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/files/...")
+}
+
+func TestSkipTests(t *testing.T) {
+	settings := Settings{
+		ProjectPath:      "testdata",
+		CheckComparisons: true,
+		SkipTests:        true,
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/skiptests")
+}
+
+func TestInvalidSettings(t *testing.T) {
+	for name, settings := range map[string]map[string]any{
+		"bad pattern":             {"exclude-files": []string{"[a-"}},
+		"unknown rule":            {"test-rules": []string{"wrapping"}},
+		"unknown kind":            {"boundary": map[string]any{"kinds": []string{"soap"}, "translators": []string{"status.Error"}}},
+		"no translator":           {"boundary": map[string]any{"kinds": []string{"grpc"}}},
+		"no signature translator": {"boundary": map[string]any{"signatures": []string{"func(net/http.ResponseWriter, *net/http.Request) error"}}},
+	} {
+		if _, err := New(settings); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	settings := Settings{
+		ProjectPath: "testdata",
+		Boundary: BoundarySettings{
+			Kinds: []string{"grpc", "http"},
+			Translators: []string{
+				"testdata/boundary/status.Error",
+				"testdata/boundary/status.Errorf",
+				"testdata/boundary/problem.Write",
+			},
+			Signatures: []string{"func(testdata/boundary/server.Context) error"},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/boundary/server")
+}
+
+func TestProvenanceResult(t *testing.T) {
+	settings := Settings{
+		ProjectPath: "testdata/provenance",
+		Whitelist:   []string{"encoding/json"},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	results := analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/provenance")
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	result := results[0].Result.(*Result)
+
+	got := make(map[string][]string)
+	for _, fn := range result.Functions {
+		var provenances []string
+		for _, ret := range fn.Returns {
+			provenances = append(provenances, ret.Provenance.String())
+		}
+		got[fn.Func.Name()] = provenances
+	}
+
+	want := map[string][]string{
+		"wrapped":       {"wrapped"},
+		"internalError": {"internal"},
+		"whitelisted":   {"whitelisted"},
+		"foreign":       {"foreign"},
+		"mixed":         {"wrapped", "internal"},
+		"bare":          {"wrapped"},
+		"noError":       nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected provenances:\n got: %v\nwant: %v", got, want)
+	}
+	if n := result.Count(ProvenanceForeign); n != 1 {
+		t.Errorf("Expected 1 foreign error, got %d", n)
+	}
+}
@@ -0,0 +1,80 @@
+package errhandle
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkNilNil checks the returns of functions returning (*T, error): `return nil, nil`
+// must be annotated, and a non-nil value must not accompany an error from a foreign package.
+func (l *Linter) checkNilNil(pass *analysis.Pass, fn *funcIndex, site returnSite, dirs *directives) {
+	ret := site.ret
+	if !isPointerErrorSignature(site.sig) || len(ret.Results) != 2 {
+		return
+	}
+	value, errExpr := ret.Results[0], ret.Results[1]
+
+	if isNil(pass, value) && isNil(pass, errExpr) {
+		// Allowed by //errhandle:nilnil on the return statement or the function
+		if dirs.allows(directiveNilNil, ret) || (site.own && fn.decl != nil && dirs.allows(directiveNilNil, fn.decl)) {
+			return
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      ret.Pos(),
+			Category: "errhandle",
+			Message:  "return nil, nil leaves callers without a value or an error; return an error or annotate with " + directivePrefix + directiveNilNil,
+		})
+		return
+	}
+
+	if isNil(pass, value) || isNil(pass, errExpr) {
+		return
+	}
+	if l.isForeignError(pass, fn, errExpr, ret.Pos()) {
+		pass.Report(analysis.Diagnostic{
+			Pos:      value.Pos(),
+			Category: "errhandle",
+			Message:  "non-nil value returned together with a foreign error; return nil instead",
+		})
+	}
+}
+
+// nilNilInScope reports whether the nil-nil checks apply to the package
+func (l *Linter) nilNilInScope(pkgPath string) bool {
+	packages := l.settings.NilNil.Packages
+	if len(packages) == 0 {
+		return l.settings.ProjectPath == "" || strings.HasPrefix(pkgPath, l.settings.ProjectPath)
+	}
+	for _, p := range packages {
+		if strings.HasPrefix(pkgPath, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForeignError reports whether errExpr is a call, or a variable assigned from a call,
+// whose error is reported as not wrapped.
+func (l *Linter) isForeignError(pass *analysis.Pass, fn *funcIndex, errExpr ast.Expr, returnPos token.Pos) bool {
+	switch errExpr.(type) {
+	case *ast.CallExpr, *ast.Ident:
+		return l.shouldReportExpr(pass, fn, errExpr, returnPos)
+	}
+	return false
+}
+
+// isPointerErrorSignature reports whether sig returns exactly (*T, error)
+func isPointerErrorSignature(sig *types.Signature) bool {
+	results := sig.Results()
+	if results.Len() != 2 {
+		return false
+	}
+	if _, ok := results.At(0).Type().(*types.Pointer); !ok {
+		return false
+	}
+	return types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type())
+}
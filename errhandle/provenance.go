@@ -0,0 +1,133 @@
+package errhandle
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Provenance classifies where a returned error comes from
+type Provenance int
+
+const (
+	// ProvenanceWrapped errors come from the wrapper package or functions annotated with //errhandle:wrapped
+	ProvenanceWrapped Provenance = iota
+	// ProvenanceInternal errors come from the project itself, see Settings.ProjectPath
+	ProvenanceInternal
+	// ProvenanceWhitelisted errors come from a package of Settings.Whitelist
+	ProvenanceWhitelisted
+	// ProvenanceForeign errors come from any other package and are reported as not wrapped
+	ProvenanceForeign
+)
+
+func (p Provenance) String() string {
+	switch p {
+	case ProvenanceWrapped:
+		return "wrapped"
+	case ProvenanceInternal:
+		return "internal"
+	case ProvenanceWhitelisted:
+		return "whitelisted"
+	case ProvenanceForeign:
+		return "foreign"
+	}
+	return "unknown"
+}
+
+// Result is the result of the errhandle analyzer, available to analyzers requiring it
+type Result struct {
+	Functions []FunctionResult
+}
+
+// Count returns the number of returned errors with provenance p across all functions
+func (r *Result) Count(p Provenance) int {
+	n := 0
+	for _, fn := range r.Functions {
+		for _, ret := range fn.Returns {
+			if ret.Provenance == p {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// FunctionResult lists the errors returned by a function, including those
+// returned from its nested function literals
+type FunctionResult struct {
+	Func    *types.Func // nil for package-level function literals
+	Pos     token.Pos
+	Returns []ReturnResult
+}
+
+// ReturnResult is a non-nil error returned by a return statement
+type ReturnResult struct {
+	Pos        token.Pos // position of the returned error, or of the return statement for bare returns
+	Provenance Provenance
+}
+
+// functionResult classifies the errors returned by an indexed function, and reports
+// whether any of its own returns, excluding those of nested function literals, is foreign
+func (l *Linter) functionResult(pass *analysis.Pass, fn *funcIndex, wrappedInDefer map[types.Object]bool) (FunctionResult, bool) {
+	result := FunctionResult{Pos: fn.root.Pos()}
+	ownForeign := false
+	if fn.decl != nil {
+		result.Func, _ = pass.TypesInfo.Defs[fn.decl.Name].(*types.Func)
+	}
+
+	for _, site := range fn.returns {
+		ret := site.ret
+		add := func(pos token.Pos, p Provenance, ok bool) {
+			if ok {
+				result.Returns = append(result.Returns, ReturnResult{Pos: pos, Provenance: p})
+				ownForeign = ownForeign || site.own && p == ProvenanceForeign
+			}
+		}
+
+		// Bare returns of named error results
+		if len(ret.Results) == 0 && site.own {
+			results := site.sig.Results()
+			for i := 0; i < results.Len(); i++ {
+				if obj := results.At(i); fn.namedErrors[obj] {
+					if wrappedInDefer[obj] {
+						add(ret.Pos(), ProvenanceWrapped, true)
+					} else {
+						p, ok := l.classifyVar(pass, fn, obj, ret.Pos())
+						add(ret.Pos(), p, ok)
+					}
+				}
+			}
+			continue
+		}
+
+		// Direct returns like "return strconv.Atoi(s)"
+		if len(ret.Results) == 1 {
+			if call, ok := ret.Results[0].(*ast.CallExpr); ok {
+				if tuple, ok := pass.TypesInfo.TypeOf(call).(*types.Tuple); ok {
+					for i := 0; i < tuple.Len(); i++ {
+						if types.Implements(tuple.At(i).Type(), errorInterface) {
+							add(call.Pos(), l.classifyCall(pass, call), true)
+							break
+						}
+					}
+					continue
+				}
+			}
+		}
+
+		for _, expr := range ret.Results {
+			if !l.isErrorType(pass, expr) {
+				continue
+			}
+			if ident, ok := expr.(*ast.Ident); ok && wrappedInDefer[pass.TypesInfo.ObjectOf(ident)] {
+				add(expr.Pos(), ProvenanceWrapped, true)
+				continue
+			}
+			p, ok := l.classifyExpr(pass, fn, expr, ret.Pos())
+			add(expr.Pos(), p, ok)
+		}
+	}
+	return result, ownForeign
+}
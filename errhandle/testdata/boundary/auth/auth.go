@@ -0,0 +1,8 @@
+// Package auth declares a handwritten server interface, not a generated gRPC one
+package auth
+
+import "context"
+
+type TokenServer interface {
+	Issue(ctx context.Context, user string) (string, error)
+}
@@ -0,0 +1,19 @@
+// Package problem writes problem+json responses
+package problem
+
+import (
+	"net/http"
+)
+
+type Problem struct {
+	Status int
+	Title  string
+}
+
+func (p *Problem) Error() string { return p.Title }
+
+// Write writes the problem to w and returns it as error
+func Write(w http.ResponseWriter, status int, title string) error {
+	w.WriteHeader(status)
+	return &Problem{Status: status, Title: title}
+}
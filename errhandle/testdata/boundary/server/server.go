@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"testdata/boundary/auth"
+	"testdata/boundary/pb"
+	"testdata/boundary/problem"
+	"testdata/boundary/status"
+)
+
+type greeter struct {
+	pb.UnimplementedGreeterServer
+	client pb.GreeterClient
+}
+
+func (s *greeter) SayHello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+	if req.Name == "" {
+		return nil, status.Error(3, "name is required")
+	}
+	n, err := strconv.Atoi(req.Name)
+	if err != nil {
+		return nil, err // want "error returned from gRPC handler must be translated by testdata/boundary/status.Error, testdata/boundary/status.Errorf, testdata/boundary/problem.Write"
+	}
+	if n < 0 {
+		return nil, errors.New("negative") // want "error returned from gRPC handler must be translated by .*"
+	}
+	if n > 100 {
+		err = status.Errorf(3, "%d is too large", n)
+		return nil, err
+	}
+	return &pb.HelloReply{Message: req.Name}, nil
+}
+
+func (s *greeter) SayGoodbye(ctx context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+	return s.client.SayHello(ctx, req) // want "error returned from gRPC handler must be translated by .*"
+}
+
+// helper is not part of the service, so the wrap rule applies
+func (s *greeter) helper(name string) (int, error) {
+	return strconv.Atoi(name) // want "error should use github.com/pkg/errors"
+}
+
+// tokens implements auth.TokenServer, which is not generated, so the wrap rule applies
+type tokens struct{}
+
+var _ auth.TokenServer = tokens{}
+
+func (tokens) Issue(ctx context.Context, user string) (string, error) {
+	n, err := strconv.Atoi(user)
+	if err != nil {
+		return "", err // want "error should use github.com/pkg/errors"
+	}
+	return strconv.Itoa(n), nil
+}
+
+func handleItem(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		return problem.Write(w, http.StatusBadRequest, "invalid id")
+	}
+	if id == 0 {
+		return errors.Wrap(err, "zero id") // want "error returned from HTTP handler must be translated by .*"
+	}
+	check := func() error {
+		_, err := strconv.Atoi(r.URL.Path)
+		return err // want "error should use github.com/pkg/errors"
+	}
+	if err := check(); err != nil {
+		return problem.Write(w, http.StatusNotFound, "not found")
+	}
+	return nil
+}
+
+// notHandler returns an error but does not take the handler parameters
+func notHandler(r *http.Request) error {
+	_, err := strconv.Atoi(r.URL.Path)
+	return err // want "error should use github.com/pkg/errors"
+}
+
+// handleHealth is an http.HandlerFunc, errors written to the response must be translated
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest) // want "error written by HTTP handler must be translated by .*"
+		return
+	}
+	if n < 0 {
+		fmt.Fprintf(w, "invalid: %v", err) // want "error written by HTTP handler must be translated by .*"
+		return
+	}
+	if err := check(n); err != nil {
+		problem.Write(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	w.Write([]byte(strconv.Itoa(n)))
+}
+
+func check(n int) error {
+	if n > 100 {
+		return errors.New("too large")
+	}
+	return nil
+}
+
+// Context is the request context of a framework whose handlers return errors
+type Context struct {
+	W http.ResponseWriter
+	R *http.Request
+}
+
+// handleContext matches a configured boundary signature
+func handleContext(c Context) error {
+	if _, err := strconv.Atoi(c.R.URL.Path); err != nil {
+		return err // want "error returned from boundary function must be translated by .*"
+	}
+	return problem.Write(c.W, http.StatusOK, "ok")
+}
@@ -0,0 +1,23 @@
+// Package status mimics google.golang.org/grpc/status
+package status
+
+import (
+	"fmt"
+)
+
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", e.code, e.msg)
+}
+
+func Error(code int, msg string) error {
+	return &statusError{code: code, msg: msg}
+}
+
+func Errorf(code int, format string, args ...any) error {
+	return &statusError{code: code, msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,84 @@
+package comparison
+
+import (
+	"database/sql"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+type notFoundError struct{}
+
+func (e *notFoundError) Error() string { return "not found" }
+
+func badEqual(err error) bool {
+	return err == sql.ErrNoRows // want "comparing errors with == does not match wrapped errors, use errors.Is"
+}
+
+func badNotEqual(err error) bool {
+	return err != io.EOF // want "comparing errors with != does not match wrapped errors, use errors.Is"
+}
+
+func badSentinelFirst(err error) bool {
+	return io.ErrUnexpectedEOF == err // want "comparing errors with == does not match wrapped errors, use errors.Is"
+}
+
+func badTypeAssertion(err error) bool {
+	_, ok := err.(*notFoundError) // want "type assertion on error does not match wrapped errors, use errors.Cause"
+	return ok
+}
+
+func badTypeSwitch(err error) string {
+	switch err.(type) { // want "type switch on error does not match wrapped errors, use errors.Cause"
+	case *notFoundError:
+		return "not found"
+	default:
+		return "unknown"
+	}
+}
+
+func badValueSwitch(err error) string {
+	switch err {
+	case nil:
+		return "ok"
+	case io.EOF, io.ErrUnexpectedEOF: // want "comparing errors in a switch case does not match wrapped errors, use errors.Is" "comparing errors in a switch case does not match wrapped errors, use errors.Is"
+		return "eof"
+	case sql.ErrNoRows: // want "comparing errors in a switch case does not match wrapped errors, use errors.Is"
+		return "not found"
+	}
+	return "unknown"
+}
+
+func goodCauseSwitch(err error) bool {
+	switch errors.Cause(err) {
+	case io.EOF:
+		return true
+	}
+	return false
+}
+
+func goodNilComparison(err error) bool {
+	return err != nil
+}
+
+func goodIs(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+func goodCauseAssertion(err error) bool {
+	_, ok := errors.Cause(err).(*notFoundError)
+	return ok
+}
+
+func goodCauseComparison(err error) bool {
+	return errors.Cause(err) == io.EOF
+}
+
+// Good: Is methods compare targets directly by design
+type timeoutError struct{}
+
+func (e *timeoutError) Error() string { return "timeout" }
+
+func (e *timeoutError) Is(target error) bool {
+	return target == io.ErrNoProgress
+}
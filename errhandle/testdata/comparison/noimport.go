@@ -0,0 +1,8 @@
+package comparison
+
+import "io"
+
+// The wrapper package is not imported here, so no fix is suggested
+func badWithoutImport(err error) bool {
+	return err == io.EOF // want "comparing errors with == does not match wrapped errors, use errors.Is"
+}
@@ -0,0 +1,79 @@
+package comparisonstd
+
+import (
+	"errors"
+	"io"
+)
+
+type notFoundError struct{}
+
+func (e *notFoundError) Error() string { return "not found" }
+
+type timeoutError struct{}
+
+func (e timeoutError) Error() string { return "timeout" }
+
+func badAssertion(err error) string {
+	target, ok := err.(*notFoundError) // want "type assertion on error does not match wrapped errors, use errors.As"
+	if !ok {
+		return "unknown"
+	}
+	return target.Error()
+}
+
+func badAssertionOnly(err error) bool {
+	_, ok := err.(*notFoundError) // want "type assertion on error does not match wrapped errors, use errors.As"
+	return ok
+}
+
+func badAssertionInit(err error) bool {
+	if _, ok := err.(timeoutError); ok { // want "type assertion on error does not match wrapped errors, use errors.As"
+		return true
+	}
+	return false
+}
+
+func badAssertionExisting(err error) (target *notFoundError, ok bool) {
+	target, ok = err.(*notFoundError) // want "type assertion on error does not match wrapped errors, use errors.As"
+	return target, ok
+}
+
+func badTypeSwitch(err error) string {
+	switch err.(type) { // want "type switch on error does not match wrapped errors, use errors.As"
+	case nil:
+		return "ok"
+	case *notFoundError, timeoutError:
+		return "expected"
+	}
+	return "unknown"
+}
+
+// No fix: the bound variable has the type of each case
+func badBoundTypeSwitch(err error) string {
+	switch e := err.(type) { // want "type switch on error does not match wrapped errors, use errors.As"
+	case *notFoundError:
+		return e.Error()
+	}
+	return "unknown"
+}
+
+// No fix: the target cannot be declared within the if statement
+func badAssertionInitTarget(err error) string {
+	if target, ok := err.(*notFoundError); ok { // want "type assertion on error does not match wrapped errors, use errors.As"
+		return target.Error()
+	}
+	return "unknown"
+}
+
+func badValueSwitch(err error) bool {
+	switch err {
+	case io.EOF: // want "comparing errors in a switch case does not match wrapped errors, use errors.Is"
+		return true
+	}
+	return false
+}
+
+func goodAs(err error) bool {
+	var target *notFoundError
+	return errors.As(err, &target)
+}
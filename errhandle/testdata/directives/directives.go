@@ -0,0 +1,92 @@
+package directives
+
+import (
+	"fmt"
+	"strconv"
+
+	"testdata/wrappedlib"
+)
+
+// Good: wrappedlib.Fetch is annotated with //errhandle:wrapped
+func goodWrappedCall() error {
+	return wrappedlib.Fetch()
+}
+
+func goodWrappedMethod(c *wrappedlib.Client) error {
+	_, err := c.Do()
+	return err
+}
+
+func badUnannotatedCall() error {
+	return wrappedlib.Plain() // want "error should use github.com/pkg/errors"
+}
+
+// Good: annotated in this package, trusted by callers elsewhere
+//
+//errhandle:wrapped
+func localHelper() error { // want localHelper:"wrapped"
+	return nil
+}
+
+// Bad: annotated, but returns errors that are not wrapped
+//
+//errhandle:wrapped // want "stale //errhandle:wrapped directive, staleHelper returns errors that are not wrapped"
+func staleHelper(s string) error { // want staleHelper:"wrapped"
+	if s == "" {
+		return localHelper()
+	}
+	_, err := strconv.Atoi(s)
+	return err // want "error should use github.com/pkg/errors"
+}
+
+// Good: only the nested function literal returns errors that are not wrapped
+//
+//errhandle:wrapped
+func nestedHelper() error { // want nestedHelper:"wrapped"
+	parse := func(s string) error {
+		_, err := strconv.Atoi(s)
+		return err // want "error should use github.com/pkg/errors"
+	}
+	_ = parse
+	return nil
+}
+
+//errhandle:wrapped // want "//errhandle:wrapped on noError, which does not return an error"
+func noError() int {
+	return 0
+}
+
+func goodIgnoredReturn() error {
+	//errhandle:ignore reason="the message is shown to users verbatim"
+	return fmt.Errorf("invalid input")
+}
+
+func goodIgnoredTrailing() error {
+	_, err := strconv.Atoi("foo")
+	return err //errhandle:ignore reason="callers only log this"
+}
+
+func badIgnoreWithoutReason() error {
+	//errhandle:ignore // want "//errhandle:ignore requires a non-empty reason"
+	return fmt.Errorf("invalid input") // want "error should use github.com/pkg/errors"
+}
+
+func badIgnoreEmptyReason() error {
+	//errhandle:ignore reason=" " // want "//errhandle:ignore requires a non-empty reason"
+	return fmt.Errorf("invalid input") // want "error should use github.com/pkg/errors"
+}
+
+func badUnusedIgnore() error {
+	//errhandle:ignore reason="nothing to suppress" // want "unused //errhandle:ignore directive"
+	return localHelper()
+}
+
+func badMisplacedIgnore() error {
+	//errhandle:ignore reason="no return follows" // want "//errhandle:ignore must be placed on or above a return statement"
+	var n int
+
+	return fmt.Errorf("invalid number %d", n) // want "error should use github.com/pkg/errors"
+}
+
+//errhandle:unknown // want "unknown directive //errhandle:unknown"
+func unknownDirective() {}
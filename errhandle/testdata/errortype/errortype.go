@@ -0,0 +1,68 @@
+package errortype
+
+// Good: implements every configured method
+type codeError struct{}
+
+func (e *codeError) Error() string { return "code error" }
+func (e *codeError) Unwrap() error { return nil }
+func (e *codeError) Cause() error  { return nil }
+
+func goodCodeError() error {
+	return new(codeError)
+}
+
+// Bad: missing both methods, reported once even though it is returned twice
+type plainError string // want "error type plainError is returned as error and must implement Unwrap, Cause"
+
+func (e plainError) Error() string { return string(e) }
+
+func badPlainError() error {
+	return plainError("plain")
+}
+
+func badPlainErrorAgain() (int, error) {
+	return 0, plainError("plain again")
+}
+
+// Bad: the methods are declared on the pointer, but the value is returned
+type valueError string // want "error type valueError is returned as error and must implement Unwrap, Cause"
+
+func (e valueError) Error() string  { return string(e) }
+func (e *valueError) Unwrap() error { return nil }
+func (e *valueError) Cause() error  { return nil }
+
+func badValueError() error {
+	return valueError("value")
+}
+
+// Bad: returned as error from a function literal
+type literalError struct{} // want "error type literalError is returned as error and must implement Cause"
+
+func (e *literalError) Error() string { return "literal error" }
+func (e *literalError) Unwrap() error { return nil }
+
+func badLiteralError() func() error {
+	return func() error {
+		return new(literalError)
+	}
+}
+
+// Good: never returned as error, only as its concrete type
+type concreteError struct{}
+
+func (e *concreteError) Error() string { return "concrete error" }
+
+func goodConcreteError() *concreteError {
+	return new(concreteError)
+}
+
+// Bad: Cause is declared with the wrong signature
+type stringCauseError struct{} // want "error type stringCauseError is returned as error and must implement Cause\\(\\) error"
+
+func (e *stringCauseError) Error() string { return "string cause error" }
+func (e *stringCauseError) Unwrap() error { return nil }
+func (e *stringCauseError) Cause() string { return "cause" }
+
+func badStringCauseError() error {
+	return new(stringCauseError)
+}
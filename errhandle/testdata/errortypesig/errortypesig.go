@@ -0,0 +1,37 @@
+package errortypesig
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Good: implements every configured method with its signature
+type codeError struct{}
+
+func (e *codeError) Error() string                 { return "code error" }
+func (e *codeError) StackTrace() errors.StackTrace { return nil }
+func (e *codeError) Code() int                     { return 1 }
+
+func goodCodeError() error {
+	return new(codeError)
+}
+
+// Bad: the methods are declared with the wrong signatures
+type wrongError struct{} // want "error type wrongError is returned as error and must implement StackTrace\\(\\) github.com/pkg/errors.StackTrace, Code\\(\\) int"
+
+func (e *wrongError) Error() string   { return "wrong error" }
+func (e *wrongError) StackTrace() int { return 0 }
+func (e *wrongError) Code() string    { return "code" }
+
+func badWrongError() error {
+	return new(wrongError)
+}
+
+// Bad: Code is missing
+type noCodeError struct{} // want "error type noCodeError is returned as error and must implement Code\\(\\) int"
+
+func (e *noCodeError) Error() string                 { return "no code error" }
+func (e *noCodeError) StackTrace() errors.StackTrace { return nil }
+
+func badNoCodeError() error {
+	return new(noCodeError)
+}
@@ -0,0 +1,9 @@
+package ent
+
+import (
+	"strconv"
+)
+
+func Parse(s string) (int, error) {
+	return strconv.Atoi(s)
+}
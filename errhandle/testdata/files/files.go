@@ -0,0 +1,13 @@
+package files
+
+import (
+	"strconv"
+)
+
+func parse(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err // want "error should use github.com/pkg/errors"
+	}
+	return n, nil
+}
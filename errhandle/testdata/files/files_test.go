@@ -0,0 +1,22 @@
+package files
+
+import (
+	"io"
+	"strconv"
+	"testing"
+)
+
+// Only the comparison rule applies to tests
+func parseForTest(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+func isEOF(err error) bool {
+	return err == io.EOF // want "comparing errors with == does not match wrapped errors, use errors.Is"
+}
+
+func TestParse(t *testing.T) {
+	if _, err := parseForTest("1"); err != nil {
+		t.Fatal(err)
+	}
+}
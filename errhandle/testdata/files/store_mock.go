@@ -0,0 +1,9 @@
+package files
+
+import (
+	"strconv"
+)
+
+func mockParse(s string) (int, error) {
+	return strconv.Atoi(s)
+}
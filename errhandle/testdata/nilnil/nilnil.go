@@ -0,0 +1,69 @@
+package nilnil
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+type user struct{ name string }
+
+func badNilNil(name string) (*user, error) {
+	if name == "" {
+		return nil, nil // want "return nil, nil leaves callers without a value or an error"
+	}
+	return &user{name: name}, nil
+}
+
+func goodNilNilAnnotated(name string) (*user, error) {
+	if name == "" {
+		return nil, nil //errhandle:nilnil not found is not an error here
+	}
+	if name == "-" {
+		//errhandle:nilnil a missing user is represented by nil
+		return nil, nil
+	}
+	return &user{name: name}, nil
+}
+
+// goodNilNilFunction may legitimately find nothing.
+//
+//errhandle:nilnil
+func goodNilNilFunction(name string) (*user, error) {
+	return nil, nil
+}
+
+func badNilNilLiteral() func() (*user, error) {
+	return func() (*user, error) {
+		return nil, nil // want "return nil, nil leaves callers without a value or an error"
+	}
+}
+
+// Good: only (*T, error) results are checked
+func goodNilNilInterface() (any, error) {
+	return nil, nil
+}
+
+func badValueWithForeignError(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return u, err // want "non-nil value returned together with a foreign error" "error should use github.com/pkg/errors"
+	}
+	return u, nil
+}
+
+func goodNilWithForeignError(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return u, nil
+}
+
+func goodValueWithWrappedError(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return u, errors.Wrap(err, "parse url")
+	}
+	return u, nil
+}
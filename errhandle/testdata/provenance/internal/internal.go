@@ -0,0 +1,9 @@
+package internal
+
+import (
+	"github.com/pkg/errors"
+)
+
+func Fail() error {
+	return errors.New("fail")
+}
@@ -0,0 +1,52 @@
+package provenance
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"testdata/provenance/internal"
+)
+
+func wrapped() error {
+	return errors.New("wrapped")
+}
+
+func internalError() error {
+	return internal.Fail()
+}
+
+func whitelisted(data []byte) error {
+	var v any
+	return json.Unmarshal(data, &v)
+}
+
+func foreign(s string) (int, error) {
+	return strconv.Atoi(s) // want "error should use github.com/pkg/errors"
+}
+
+func mixed(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if n < 0 {
+		return 0, internal.Fail()
+	}
+	return n, nil
+}
+
+func bare(s string) (n int, err error) {
+	defer func() {
+		if err != nil {
+			err = errors.WithStack(err)
+		}
+	}()
+	n, err = strconv.Atoi(s)
+	return
+}
+
+func noError(s string) int {
+	return len(s)
+}
@@ -0,0 +1,9 @@
+package skiptests
+
+import (
+	"strconv"
+)
+
+func parse(s string) (int, error) {
+	return strconv.Atoi(s) // want "error should use github.com/pkg/errors"
+}
@@ -0,0 +1,21 @@
+package skiptests
+
+import (
+	"io"
+	"strconv"
+	"testing"
+)
+
+func parseForTest(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+func isEOF(err error) bool {
+	return err == io.EOF
+}
+
+func TestParse(t *testing.T) {
+	if _, err := parseForTest("1"); err != nil && !isEOF(err) {
+		t.Fatal(err)
+	}
+}
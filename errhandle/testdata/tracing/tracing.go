@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Bad: saved is traced as of its assignment, before err is wrapped
+func copiedBeforeWrap(s string) error {
+	_, err := strconv.Atoi(s)
+	saved := err
+	err = errors.Wrap(err, "parse")
+	_ = err
+	return saved // want "error should use github.com/pkg/errors"
+}
+
+// Good: saved is traced as of its assignment, after err is wrapped
+func copiedAfterWrap(s string) error {
+	_, err := strconv.Atoi(s)
+	err = errors.Wrap(err, "parse")
+	saved := err
+	_, err = strconv.Atoi(s)
+	_ = err
+	return saved
+}
+
+// Bad: swapped variables are traced back to the parameter, and terminate
+func swapped(a, b error) error {
+	a = b
+	b = a
+	return b // want "error should use github.com/pkg/errors"
+}
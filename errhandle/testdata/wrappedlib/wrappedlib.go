@@ -0,0 +1,24 @@
+package wrappedlib
+
+import "github.com/pkg/errors"
+
+// Fetch wraps every error it returns.
+//
+//errhandle:wrapped
+func Fetch() error {
+	return errors.New("fetch failed")
+}
+
+// Plain carries no directive.
+func Plain() error {
+	return errors.New("plain failed")
+}
+
+type Client struct{}
+
+// Do wraps every error it returns.
+//
+//errhandle:wrapped
+func (c *Client) Do() (int, error) {
+	return 0, errors.New("do failed")
+}
@@ -1,22 +1,29 @@
 package gormlint
 
 import (
+	"fmt"
 	"go/ast"
 	"go/types"
 
 	"github.com/golangci/plugin-module-register/register"
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
 )
 
-// This linter checks GORM-specific usages.
-// First rule: (*gorm.DB).First must be called with exactly 1 argument (dest),
-// disallowing usage of its variadic conds parameter.
+// This linter checks GORM-specific usages with rules targeting (*gorm.DB) methods,
+// see rules.go. For example (*gorm.DB).First must be called with exactly 1 argument
+// (dest), disallowing usage of its variadic conds parameter.
 
 func init() {
 	register.Plugin("gormlint", New)
 }
 
-type Settings struct{}
+type Settings struct {
+	// Rules configures rules by ID, e.g. "no-conds". Only no-conds is enabled by default,
+	// the other rules must be enabled here.
+	Rules map[string]RuleSettings `json:"rules"`
+}
 
 type Linter struct {
 	settings Settings
@@ -27,6 +34,11 @@ func New(settings any) (register.LinterPlugin, error) {
 	if err != nil {
 		return nil, err
 	}
+	for id := range s.Rules {
+		if ruleByID(id) == nil {
+			return nil, fmt.Errorf("unknown rule %q", id)
+		}
+	}
 	return &Linter{settings: s}, nil
 }
 
@@ -34,8 +46,10 @@ func (l *Linter) BuildAnalyzers() ([]*analysis.Analyzer, error) {
 	return []*analysis.Analyzer{
 		{
 			Name: "gormlint",
-			Doc:  "Check GORM usages (First must have exactly one argument)",
+			Doc:  "Check GORM usages, such as finders called with variadic conds",
 			Run:  l.run,
+
+			Requires: []*analysis.Analyzer{inspect.Analyzer},
 		},
 	}, nil
 }
@@ -46,39 +60,49 @@ func (l *Linter) GetLoadMode() string {
 }
 
 func (l *Linter) run(pass *analysis.Pass) (any, error) {
-	for _, file := range pass.Files {
-		ast.Inspect(file, func(n ast.Node) bool {
-			call, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
-
-			sel, ok := call.Fun.(*ast.SelectorExpr)
-			if !ok {
-				return true
-			}
-
-			// Only care about First method
-			if sel.Sel == nil || sel.Sel.Name != "First" {
-				return true
-			}
-
-			// Confirm receiver is *gorm.DB (or DB/pointer) via type info
-			if !isGormDBReceiver(pass, sel.X) {
-				return true
-			}
-
-			// Enforce exactly 1 argument (dest)
-			if len(call.Args) != 1 {
-				pass.Report(analysis.Diagnostic{
-					Pos:      call.Pos(),
-					Category: "gormlint",
-					Message:  "gorm DB.First must be called with exactly 1 argument (dest); do not use variadic conds",
-				})
-			}
-			return true
-		})
+	byMethod := l.enabledRules()
+	if len(byMethod) == 0 {
+		return nil, nil
 	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{(*ast.CallExpr)(nil)}
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		targeted := byMethod[sel.Sel.Name]
+		if len(targeted) == 0 {
+			return true
+		}
+
+		// Confirm receiver is *gorm.DB (or DB/pointer) via type info
+		if !isGormDBReceiver(pass, sel.X) {
+			return true
+		}
+		method, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+		if !ok {
+			return true
+		}
+
+		for _, r := range targeted {
+			r.check(&callContext{
+				pass:     pass,
+				call:     call,
+				method:   method,
+				stack:    stack,
+				settings: l.settings.Rules[r.id],
+			})
+		}
+		return true
+	})
 	return nil, nil
 }
 
@@ -17,3 +17,93 @@ func TestGormFirstArgs(t *testing.T) {
 	}
 	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/testpkg")
 }
+
+func TestRuleSettings(t *testing.T) {
+	settings := Settings{
+		Rules: map[string]RuleSettings{
+			"no-conds": {Methods: []string{"First", "Scan"}},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/rules")
+}
+
+func TestDisabledRule(t *testing.T) {
+	disabled := false
+	settings := Settings{
+		Rules: map[string]RuleSettings{
+			"no-conds": {Enabled: &disabled},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/disabled")
+}
+
+func TestInvalidSettings(t *testing.T) {
+	for name, settings := range map[string]map[string]any{
+		"unknown rule": {"rules": map[string]any{"first-args": map[string]any{"enabled": true}}},
+	} {
+		if _, err := New(settings); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestUnscopedMutation(t *testing.T) {
+	enabled := true
+	settings := Settings{
+		Rules: map[string]RuleSettings{
+			"unscoped-mutation": {Enabled: &enabled},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/unscoped")
+}
+
+func TestResultChecks(t *testing.T) {
+	enabled := true
+	settings := Settings{
+		Rules: map[string]RuleSettings{
+			"unchecked-error": {Enabled: &enabled},
+			"discarded-chain": {Enabled: &enabled},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/checks")
+}
+
+func TestSQLInjection(t *testing.T) {
+	enabled := true
+	settings := Settings{
+		Rules: map[string]RuleSettings{
+			"sql-injection": {Enabled: &enabled, Trusted: []string{"testdata/sqlinjection/sqlutil.QuoteColumn"}},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/sqlinjection")
+}
@@ -0,0 +1,115 @@
+package gormlint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// checkUncheckedError reports finisher calls whose *gorm.DB result is discarded, or
+// assigned to a variable that is never used, so that their Error is never read
+func checkUncheckedError(c *callContext) {
+	parent, child := resultParent(c.stack)
+	switch n := parent.(type) {
+	case *ast.ExprStmt, *ast.GoStmt, *ast.DeferStmt:
+	case *ast.AssignStmt:
+		if v := assignedVar(c, n.Lhs, n.Rhs, child); v != nil && c.usedAfter(v, n.End()) {
+			return
+		}
+	case *ast.ValueSpec:
+		for i, value := range n.Values {
+			if value == child && i < len(n.Names) {
+				if v, ok := c.pass.TypesInfo.Defs[n.Names[i]].(*types.Var); ok && c.usedAfter(v, n.End()) {
+					return
+				}
+			}
+		}
+	default:
+		// Read with .Error, chained further, returned or passed to a call
+		return
+	}
+	c.reportf(c.call, "gorm DB.%s result is not checked, read its Error field", c.method.Name())
+}
+
+// checkDiscardedChain reports chain builder calls in statement position or assigned
+// to the blank identifier, since they return a new *gorm.DB with the added clause
+func checkDiscardedChain(c *callContext) {
+	parent, child := resultParent(c.stack)
+	switch n := parent.(type) {
+	case *ast.ExprStmt:
+	case *ast.AssignStmt:
+		if len(n.Lhs) != len(n.Rhs) {
+			return
+		}
+		for i, rhs := range n.Rhs {
+			if ident, ok := n.Lhs[i].(*ast.Ident); rhs == child && (!ok || ident.Name != "_") {
+				return
+			}
+		}
+	default:
+		return
+	}
+	c.reportf(c.call, "gorm DB.%s result is discarded, it returns a new *gorm.DB that must be used", c.method.Name())
+}
+
+// resultParent returns the node receiving the result of the call on top of stack,
+// skipping parentheses, and its child holding the call
+func resultParent(stack []ast.Node) (ast.Node, ast.Node) {
+	child := stack[len(stack)-1]
+	for i := len(stack) - 2; i >= 0; i-- {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			return stack[i], child
+		}
+		child = stack[i]
+	}
+	return nil, child
+}
+
+// assignedVar returns the *gorm.DB variable that value is assigned to, if any
+func assignedVar(c *callContext, lhs, rhs []ast.Expr, value ast.Node) *types.Var {
+	if len(lhs) != len(rhs) {
+		return nil
+	}
+	for i, expr := range rhs {
+		if expr != value {
+			continue
+		}
+		ident, ok := lhs[i].(*ast.Ident)
+		if !ok || ident.Name == "_" || !isGormDBReceiver(c.pass, ident) {
+			return nil
+		}
+		v, _ := c.pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		return v
+	}
+	return nil
+}
+
+// usedAfter reports whether v is read after pos in the function enclosing the call
+func (c *callContext) usedAfter(v *types.Var, pos token.Pos) bool {
+	body := enclosingFuncBody(c.stack)
+	if body == nil {
+		return true // package-level variables may be used anywhere
+	}
+	used := false
+	assigned := make(map[*ast.Ident]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if used || n == nil || n.End() <= pos {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// Assigning v again doesn't read it
+			for _, lhs := range n.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					assigned[ident] = true
+				}
+			}
+		case *ast.Ident:
+			if !assigned[n] && n.Pos() > pos && c.pass.TypesInfo.Uses[n] == v {
+				used = true
+			}
+		}
+		return true
+	})
+	return used
+}
@@ -0,0 +1,127 @@
+package gormlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// RuleSettings configures a rule, see Settings.Rules
+type RuleSettings struct {
+	// Enabled enables or disables the rule, which otherwise uses its own default
+	Enabled *bool `json:"enabled"`
+	// Methods overrides the (*gorm.DB) methods targeted by the rule
+	Methods []string `json:"methods"`
+	// Trusted lists the full names of functions returning safe SQL for the sql-injection
+	// rule, e.g. "example.com/project/sqlutil.QuoteColumn"
+	Trusted []string `json:"trusted"`
+}
+
+// rule is a check of calls to (*gorm.DB) methods
+type rule struct {
+	id      string
+	enabled bool     // by default
+	methods []string // targeted by default
+	check   func(c *callContext)
+}
+
+// callContext is a call to a method targeted by a rule
+type callContext struct {
+	pass     *analysis.Pass
+	call     *ast.CallExpr
+	method   *types.Func
+	stack    []ast.Node // enclosing nodes, with the call on top
+	settings RuleSettings
+}
+
+func (c *callContext) reportf(node ast.Node, format string, args ...any) {
+	c.pass.Report(analysis.Diagnostic{
+		Pos:      node.Pos(),
+		Category: "gormlint",
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// rules lists all the rules by ID
+var rules = []*rule{
+	{
+		// Finder and Delete methods must not use their variadic conds, which are easily
+		// mistaken for the primary key and silently ignored when dest already has one
+		id:      "no-conds",
+		enabled: true,
+		methods: []string{"First", "Take", "Last", "Find", "FirstOrInit", "FirstOrCreate", "Delete"},
+		check:   checkNoConds,
+	},
+	{
+		// Mutations must be scoped by conditions or a primary key, unless the chain
+		// opts in with Session(&gorm.Session{AllowGlobalUpdate: true}). Opt-in, like
+		// every rule added after no-conds, so that upgrades report nothing new.
+		id:      "unscoped-mutation",
+		methods: []string{"Delete", "Update", "Updates", "UpdateColumn", "UpdateColumns"},
+		check:   checkUnscoped,
+	},
+	{
+		// Finishers must have their Error read, opt-in
+		id:      "unchecked-error",
+		methods: []string{"First", "Find", "Create", "Save", "Delete", "Exec", "Scan"},
+		check:   checkUncheckedError,
+	},
+	{
+		// Chain builders return a new *gorm.DB, opt-in
+		id: "discarded-chain",
+		methods: []string{
+			"Model", "Clauses", "Table", "Distinct", "Select", "Omit", "MapColumns", "Where", "Not", "Or",
+			"Joins", "InnerJoins", "Group", "Having", "Order", "Limit", "Offset", "Scopes", "Preload",
+			"Attrs", "Assign", "Unscoped", "Raw", "Session", "WithContext", "Debug",
+		},
+		check: checkDiscardedChain,
+	},
+	{
+		// SQL arguments must be built only from constants, values go to placeholders, opt-in
+		id:      "sql-injection",
+		methods: []string{"Where", "Or", "Not", "Having", "Joins", "InnerJoins", "Group", "Order", "Select", "Raw", "Exec"},
+		check:   checkSQLInjection,
+	},
+}
+
+func ruleByID(id string) *rule {
+	i := slices.IndexFunc(rules, func(r *rule) bool { return r.id == id })
+	if i < 0 {
+		return nil
+	}
+	return rules[i]
+}
+
+// enabledRules returns the enabled rules by the method names they target
+func (l *Linter) enabledRules() map[string][]*rule {
+	byMethod := make(map[string][]*rule)
+	for _, r := range rules {
+		s := l.settings.Rules[r.id]
+		if s.Enabled != nil && !*s.Enabled || s.Enabled == nil && !r.enabled {
+			continue
+		}
+		methods := r.methods
+		if s.Methods != nil {
+			methods = s.Methods
+		}
+		for _, m := range methods {
+			byMethod[m] = append(byMethod[m], r)
+		}
+	}
+	return byMethod
+}
+
+// checkNoConds reports calls with more than the first argument, e.g. db.First(&u, 10)
+func checkNoConds(c *callContext) {
+	if len(c.call.Args) == 1 {
+		return
+	}
+	params := c.method.Type().(*types.Signature).Params()
+	if params.Len() == 0 {
+		return
+	}
+	c.reportf(c.call, "gorm DB.%s must be called with exactly 1 argument (%s); do not use variadic conds", c.method.Name(), params.At(0).Name())
+}
@@ -0,0 +1,150 @@
+package gormlint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// checkSQLInjection reports SQL arguments that are not built only from constants,
+// e.g. db.Where("name = '" + name + "'") or db.Order(sort)
+func checkSQLInjection(c *callContext) {
+	if len(c.call.Args) == 0 {
+		return
+	}
+	arg := c.call.Args[0]
+
+	// Struct, map and clause conditions are not SQL strings
+	basic, ok := c.pass.TypesInfo.TypeOf(arg).Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsString == 0 {
+		return
+	}
+	if c.isSafeSQL(arg, make(map[*types.Var]bool)) {
+		return
+	}
+	c.reportf(arg, "gorm DB.%s SQL argument is not built only from constants, pass values as placeholder arguments", c.method.Name())
+}
+
+// isSafeSQL reports whether expr is built only from constants, calls to trusted
+// functions and local variables assigned only such values
+func (c *callContext) isSafeSQL(expr ast.Expr, visiting map[*types.Var]bool) bool {
+	if tv, ok := c.pass.TypesInfo.Types[expr]; ok && tv.Value != nil {
+		return true
+	}
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && c.isSafeSQL(e.X, visiting) && c.isSafeSQL(e.Y, visiting)
+	case *ast.CallExpr:
+		// Conversions like string(b)
+		if c.pass.TypesInfo.Types[e.Fun].IsType() {
+			return len(e.Args) == 1 && c.isSafeSQL(e.Args[0], visiting)
+		}
+		fn, ok := typeutil.Callee(c.pass.TypesInfo, e).(*types.Func)
+		if !ok {
+			return false
+		}
+		if slices.Contains(c.settings.Trusted, fn.FullName()) {
+			return true
+		}
+		if fn.FullName() == "fmt.Sprintf" {
+			for _, arg := range e.Args {
+				if !c.isSafeSQL(arg, visiting) {
+					return false
+				}
+			}
+			return true
+		}
+	case *ast.Ident:
+		v, ok := c.pass.TypesInfo.Uses[e].(*types.Var)
+		if !ok {
+			return false
+		}
+		return c.isSafeVar(v, visiting)
+	}
+	return false
+}
+
+// isSafeVar reports whether the local variable v is declared and only assigned in the
+// function enclosing the call, with safe values
+func (c *callContext) isSafeVar(v *types.Var, visiting map[*types.Var]bool) bool {
+	if visiting[v] {
+		return true // q = q + "...", the other assignments decide
+	}
+	visiting[v] = true
+	defer delete(visiting, v)
+
+	body := outermostFuncBody(c.stack)
+	if body == nil {
+		return false
+	}
+	declared, safe := false, true
+	check := func(ident *ast.Ident, value ast.Expr) {
+		if c.pass.TypesInfo.ObjectOf(ident) != v {
+			return
+		}
+		if ident.Pos() == v.Pos() {
+			declared = true
+		}
+		if value == nil || !c.isSafeSQL(value, visiting) {
+			safe = false
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if !safe {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				var value ast.Expr
+				if len(n.Lhs) == len(n.Rhs) {
+					value = n.Rhs[i]
+				}
+				check(ident, value) // multi-value calls are unknown
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				switch {
+				case len(n.Values) == 0:
+					// The zero value
+					if name.Pos() == v.Pos() {
+						declared = true
+					}
+				case len(n.Values) == len(n.Names):
+					check(name, n.Values[i])
+				default:
+					check(name, nil)
+				}
+			}
+		case *ast.UnaryExpr:
+			// Written through a pointer
+			if ident, ok := ast.Unparen(n.X).(*ast.Ident); ok && n.Op == token.AND && c.pass.TypesInfo.Uses[ident] == v {
+				safe = false
+			}
+		}
+		return true
+	})
+	// Parameters, range variables and variables of other functions are unknown
+	return declared && safe
+}
+
+// outermostFuncBody returns the body of the outermost function in stack, so that
+// variables captured by function literals are resolved too
+func outermostFuncBody(stack []ast.Node) *ast.BlockStmt {
+	for _, n := range stack {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			return n.Body
+		case *ast.FuncLit:
+			return n.Body
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type User struct {
+	ID   int
+	Name string
+}
+
+func checked(db *gorm.DB) error {
+	var u User
+	if err := db.Where("name = ?", "a").First(&u).Error; err != nil {
+		return err
+	}
+
+	result := db.Create(&User{Name: "a"})
+	if result.RowsAffected == 0 {
+		return errors.New("not created")
+	}
+
+	var saved *gorm.DB
+	saved = db.Save(&u)
+	if saved.Error != nil {
+		return saved.Error
+	}
+
+	var users []User
+	var found = db.Find(&users)
+	_ = found.Error
+
+	logError(db.Exec("UPDATE users SET name = ? WHERE id = ?", "b", 1))
+	return (db.Delete(&User{ID: 1})).Error
+}
+
+func logError(tx *gorm.DB) {}
+
+func handedOff(db *gorm.DB) *gorm.DB {
+	var n int64
+	db.Find(&[]User{}).Count(&n)
+	return db.Where("id = ?", 1).Find(&[]User{})
+}
+
+func unchecked(db *gorm.DB) {
+	var u User
+	db.Where("name = ?", "a").First(&u)          // want "gorm DB.First result is not checked, read its Error field"
+	(db.Create(&User{Name: "a"}))                // want "gorm DB.Create result is not checked, read its Error field"
+	_ = db.Save(&u)                              // want "gorm DB.Save result is not checked, read its Error field"
+	defer db.Delete(&User{ID: 1})                // want "gorm DB.Delete result is not checked, read its Error field"
+	go db.Exec("DELETE FROM users WHERE id = 1") // want "gorm DB.Exec result is not checked, read its Error field"
+
+	reused := db.Raw("SELECT 1").Scan(&u)
+	_ = reused.Error
+	reused = db.Find(&u) // want "gorm DB.Find result is not checked, read its Error field"
+}
+
+func discarded(db *gorm.DB) {
+	tx := db.Model(&User{})
+	tx.Where("name = ?", "a")      // want "gorm DB.Where result is discarded, it returns a new \\*gorm.DB that must be used"
+	(tx.Order("id"))               // want "gorm DB.Order result is discarded, it returns a new \\*gorm.DB that must be used"
+	_ = db.Table("users").Limit(1) // want "gorm DB.Limit result is discarded, it returns a new \\*gorm.DB that must be used"
+
+	tx = tx.Where("name = ?", "b")
+	query, other := db.Select("id"), db.Omit("name")
+	_, _ = query, other
+
+	var users []User
+	_ = tx.Preload("Orders").Find(&users).Error
+}
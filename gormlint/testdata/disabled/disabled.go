@@ -0,0 +1,12 @@
+package disabled
+
+import "gorm.io/gorm"
+
+type User struct{ ID int }
+
+// The no-conds rule is disabled
+func disabled(db *gorm.DB) {
+	var u User
+	db.First(&u, 10)
+	db.Delete(&User{}, 10)
+}
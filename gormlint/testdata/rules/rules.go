@@ -0,0 +1,13 @@
+package rules
+
+import "gorm.io/gorm"
+
+type User struct{ ID int }
+
+// The no-conds rule is configured to target First and Scan only
+func configured(db *gorm.DB) {
+	var u User
+	db.First(&u, 10) // want "gorm DB.First must be called with exactly 1 argument \\(dest\\)"
+	db.Find(&u, 10)
+	db.Delete(&User{}, 10)
+}
@@ -0,0 +1,75 @@
+package sqlinjection
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"testdata/sqlinjection/sqlutil"
+)
+
+type User struct {
+	ID   int
+	Name string
+}
+
+const usersTable = "users"
+
+func safe(db *gorm.DB, name string, sort string, admin bool) {
+	var users []User
+	db.Where("name = ?", name).Find(&users)
+	db.Where("name = ? AND id > ?", name, 0).Or("admin = ?", admin).Find(&users)
+	db.Where(&User{Name: name}).Find(&users)
+	db.Where(map[string]any{"name": name}).Find(&users)
+	db.Raw("SELECT * FROM "+usersTable+" WHERE name = ?", name).Scan(&users)
+	db.Raw(fmt.Sprintf("SELECT * FROM %s WHERE id = %d", usersTable, 1)).Scan(&users)
+	db.Order(sqlutil.QuoteColumn(sort)).Find(&users)
+	db.Order(sqlutil.QuoteColumn(sort) + " DESC").Find(&users)
+
+	query := "SELECT * FROM users"
+	if admin {
+		query += " WHERE admin"
+	}
+	db.Raw(query).Scan(&users)
+
+	var order string
+	if admin {
+		order = "id"
+	}
+	db.Order(order).Find(&users)
+	db.Exec(string("DELETE FROM users WHERE id = ?"), 1)
+}
+
+func unsafe(db *gorm.DB, name string, sort string, statements []string) {
+	var users []User
+	db.Where("name = '" + name + "'").Find(&users)                                  // want "gorm DB.Where SQL argument is not built only from constants, pass values as placeholder arguments"
+	db.Raw(fmt.Sprintf("SELECT * FROM users WHERE name = '%s'", name)).Scan(&users) // want "gorm DB.Raw SQL argument is not built only from constants"
+	db.Order(sort).Find(&users)                                                     // want "gorm DB.Order SQL argument is not built only from constants"
+	db.Or("name = " + name).Find(&users)                                            // want "gorm DB.Or SQL argument is not built only from constants"
+
+	query := "SELECT * FROM users"
+	if name != "" {
+		query += " WHERE name = '" + name + "'"
+	}
+	db.Raw(query).Scan(&users) // want "gorm DB.Raw SQL argument is not built only from constants"
+
+	for _, s := range statements {
+		db.Exec(s) // want "gorm DB.Exec SQL argument is not built only from constants"
+	}
+
+	column, _ := lookup(sort)
+	db.Order(column).Find(&users) // want "gorm DB.Order SQL argument is not built only from constants"
+
+	filter := "name = 'a'"
+	overwrite(&filter, name)
+	db.Where(filter).Find(&users) // want "gorm DB.Where SQL argument is not built only from constants"
+
+	run := func() {
+		db.Exec(query) // want "gorm DB.Exec SQL argument is not built only from constants"
+	}
+	run()
+}
+
+func lookup(key string) (string, bool) { return key, true }
+
+func overwrite(s *string, value string) { *s = value }
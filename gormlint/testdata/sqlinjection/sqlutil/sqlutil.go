@@ -0,0 +1,7 @@
+// Package sqlutil builds SQL from trusted input
+package sqlutil
+
+// QuoteColumn quotes a column name, rejecting unknown columns
+func QuoteColumn(name string) string {
+	return `"` + name + `"`
+}
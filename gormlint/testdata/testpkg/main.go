@@ -7,6 +7,14 @@ type User struct{ ID int }
 func good(db *gorm.DB) {
 	var u User
 	db.First(&u)
+	db.Where("id = ?", 10).Take(&u)
+	db.Last(&u)
+
+	var users []User
+	db.Where("id > ?", 10).Find(&users)
+	db.FirstOrInit(&u)
+	db.FirstOrCreate(&u)
+	db.Where("id = ?", 10).Delete(&User{})
 }
 
 func bad(db *gorm.DB) {
@@ -14,4 +22,14 @@ func bad(db *gorm.DB) {
 	db.First(&u, 10) // want "gorm DB.First must be called with exactly 1 argument"
 }
 
+func badConds(db *gorm.DB) {
+	var u User
+	db.Take(&u, "id = ?", 10)         // want "gorm DB.Take must be called with exactly 1 argument \\(dest\\)"
+	db.Last(&u, 10)                   // want "gorm DB.Last must be called with exactly 1 argument \\(dest\\)"
+	db.Find(&u, "id = ?", 10)         // want "gorm DB.Find must be called with exactly 1 argument \\(dest\\)"
+	db.FirstOrInit(&u, User{ID: 10})  // want "gorm DB.FirstOrInit must be called with exactly 1 argument \\(dest\\)"
+	db.FirstOrCreate(&u, User{ID: 1}) // want "gorm DB.FirstOrCreate must be called with exactly 1 argument \\(dest\\)"
+	db.Delete(&User{}, 10)            // want "gorm DB.Delete must be called with exactly 1 argument \\(value\\)"
+}
+
 // no runtime setup needed for analysistest
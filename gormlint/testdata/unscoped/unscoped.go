@@ -0,0 +1,107 @@
+package unscoped
+
+import "gorm.io/gorm"
+
+type User struct {
+	ID   int
+	Name string
+}
+
+type Order struct {
+	Code string `gorm:"primaryKey"`
+}
+
+type Account struct {
+	gorm.Model
+	Name string
+}
+
+func scopedByWhere(db *gorm.DB) {
+	db.Model(&User{}).Where("name = ?", "a").Updates(map[string]any{"name": "b"})
+	db.Where("code = ?", "a").Delete(&Order{})
+	db.Where("id = ?", 1).Model(&User{}).Update("name", "b")
+	db.Scopes(func(tx *gorm.DB) *gorm.DB { return tx.Where("id > 0") }).Delete(&User{})
+	db.Model(&User{}).Where("id = ?", 1).UpdateColumn("name", "b")
+}
+
+func scopedByNotAndOr(db *gorm.DB) {
+	db.Not("name = ?", "a").Delete(&User{})
+	db.Model(&User{}).Or("name = ?", "a").Updates(map[string]any{"name": "b"})
+	db.Table("users").Not(map[string]any{"name": "a"}).UpdateColumn("name", "b")
+}
+
+func scopedByPrimaryKey(db *gorm.DB, u *User, users []User) {
+	db.Delete(&User{ID: 1})
+	db.Delete(&Order{Code: "a"})
+	db.Delete(&Account{Model: gorm.Model{ID: 1}})
+	db.Delete(u)
+	db.Delete(&users)
+	db.Delete(&User{}, 1) // want "gorm DB.Delete must be called with exactly 1 argument"
+	db.Model(u).Updates(map[string]any{"name": "b"})
+	db.Model(&User{ID: 1}).UpdateColumns(User{Name: "b"})
+	db.Updates(&User{ID: 1, Name: "b"})
+	db.Save(&User{})
+}
+
+func unscoped(db *gorm.DB) {
+	db.Model(&User{}).Updates(map[string]any{"name": "b"})  // want "gorm DB.Updates is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	db.Delete(&Order{})                                     // want "gorm DB.Delete is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	db.Delete(&User{Name: "a"})                             // want "gorm DB.Delete is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	db.Model(&Account{}).Update("name", "b")                // want "gorm DB.Update is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	db.Table("users").UpdateColumn("name", "b")             // want "gorm DB.UpdateColumn is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	db.Model(&User{}).UpdateColumns(User{ID: 1, Name: "b"}) // want "gorm DB.UpdateColumns is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	(db.Model(&User{}).Debug()).Updates(map[string]any{})   // want "gorm DB.Updates is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	db.Unscoped().Model(&User{}).Update("name", "b")        // want "gorm DB.Update is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+}
+
+func splitChains(db *gorm.DB, admin bool) {
+	// Conditions added within a branch don't scope the other paths
+	tx := db.Model(&User{})
+	if admin {
+		tx = tx.Where("admin = ?", true)
+	}
+	tx.Update("name", "b") // want "gorm DB.Update is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+
+	filtered := db.Model(&User{}).Where("id > ?", 0)
+	if admin {
+		filtered = filtered.Where("admin = ?", true)
+	}
+	filtered.Update("name", "b")
+
+	replaced := db.Where("id = ?", 1)
+	if admin {
+		replaced = db.Model(&User{})
+	}
+	replaced.Update("name", "b") // want "gorm DB.Update is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+
+	if scopedInit := db.Where("id = ?", 1); admin {
+		scopedInit.Model(&User{}).Update("name", "b")
+	}
+
+	scoped := db.Where("id = ?", 1)
+	scoped = scoped.Model(&User{})
+	scoped.Update("name", "b")
+
+	var model = db.Model(&User{})
+	model.Update("name", "b") // want "gorm DB.Update is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+
+	reassigned := db.Where("id = ?", 1)
+	reassigned = db.Model(&User{})
+	reassigned.Update("name", "b") // want "gorm DB.Update is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+
+	run := func() {
+		inner := db.Model(&User{})
+		inner.Updates(map[string]any{}) // want "gorm DB.Updates is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	}
+	run()
+}
+
+func globalUpdate(db *gorm.DB) {
+	db.Session(&gorm.Session{AllowGlobalUpdate: true}).Model(&User{}).Update("name", "b")
+
+	global := db.Session(&gorm.Session{AllowGlobalUpdate: true})
+	global.Delete(&Order{})
+
+	db.Session(&gorm.Session{AllowGlobalUpdate: false}).Delete(&Order{}) // want "gorm DB.Delete is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+	db.Session(&gorm.Session{DryRun: true}).Delete(&Order{})             // want "gorm DB.Delete is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row"
+}
@@ -0,0 +1,266 @@
+package gormlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// checkUnscoped reports mutations whose chain has no conditions, so that they
+// affect every row, e.g. db.Model(&User{}).Updates(map[string]any{...})
+func checkUnscoped(c *callContext) {
+	// Inline conds of Delete are conditions
+	if c.method.Name() == "Delete" && len(c.call.Args) > 1 {
+		return
+	}
+	if c.method.Name() == "Delete" && hasPrimaryKey(c, c.call.Args[0]) {
+		return
+	}
+
+	sel := ast.Unparen(c.call.Fun).(*ast.SelectorExpr)
+	scoped, model := c.chainScoped(sel.X, c.call.Pos())
+	if scoped {
+		return
+	}
+	// Without a model, Updates and UpdateColumns use the primary key of their value
+	if !model && (c.method.Name() == "Updates" || c.method.Name() == "UpdateColumns") && hasPrimaryKey(c, c.call.Args[0]) {
+		return
+	}
+	c.reportf(c.call, "gorm DB.%s is not scoped by Where, Not, Or, Scopes or a primary key and may affect every row", c.method.Name())
+}
+
+// chainScoped reports whether the chain of (*gorm.DB) calls ending with expr, as of pos,
+// has conditions, and whether it sets a model. Variables are resolved to their latest
+// assignment before pos in the enclosing function.
+func (c *callContext) chainScoped(expr ast.Expr, pos token.Pos) (scoped, model bool) {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.CallExpr:
+			sel, ok := ast.Unparen(e.Fun).(*ast.SelectorExpr)
+			if !ok || !isGormDBReceiver(c.pass, sel.X) {
+				return false, model
+			}
+			switch sel.Sel.Name {
+			case "Where", "Not", "Or", "Scopes":
+				return true, model
+			case "Session":
+				if len(e.Args) == 1 && allowsGlobalUpdate(c, e.Args[0]) {
+					return true, model
+				}
+			case "Model":
+				model = true
+				if len(e.Args) == 1 && hasPrimaryKey(c, e.Args[0]) {
+					return true, model
+				}
+			}
+			expr = sel.X
+		case *ast.Ident:
+			v, ok := c.pass.TypesInfo.Uses[e].(*types.Var)
+			if !ok {
+				return false, model
+			}
+			latest, branches := c.assignments(v, pos)
+			if latest == nil || latest.value == nil {
+				return false, model // a parameter or an unknown value
+			}
+			// Assignments within branches may or may not happen, so they must be
+			// scoped as well as the latest assignment on every path
+			for _, a := range branches {
+				if a.value == nil {
+					return false, model
+				}
+				scoped, m := c.chainScoped(a.value, a.pos)
+				model = model || m
+				if !scoped {
+					return false, model
+				}
+			}
+			expr, pos = latest.value, latest.pos
+		default:
+			return false, model
+		}
+	}
+}
+
+// assignment is a value assigned to a variable, nil for multi-value assignments
+type assignment struct {
+	value ast.Expr
+	pos   token.Pos
+}
+
+// assignments returns the latest assignment to v before pos that happens on every path
+// to the call, in the function enclosing it, and the later assignments before pos that
+// happen within branches
+func (c *callContext) assignments(v *types.Var, pos token.Pos) (*assignment, []assignment) {
+	body := enclosingFuncBody(c.stack)
+	if body == nil {
+		return nil, nil
+	}
+
+	var all []assignment
+	var onEveryPath []bool
+	record := func(lhs []ast.Expr, values []ast.Expr, stmt ast.Node, parent ast.Node) {
+		for i, expr := range lhs {
+			if ident, ok := expr.(*ast.Ident); ok && c.pass.TypesInfo.ObjectOf(ident) == v {
+				a := assignment{pos: stmt.Pos()}
+				if len(lhs) == len(values) {
+					a.value = values[i]
+				}
+				all = append(all, a)
+				onEveryPath = append(onEveryPath, c.dominates(stmt, parent))
+			}
+		}
+	}
+	var parents []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			parents = parents[:len(parents)-1]
+			return true
+		}
+		if n.Pos() >= pos {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			record(n.Lhs, n.Rhs, n, parents[len(parents)-1])
+		case *ast.ValueSpec:
+			// Declared in the statement list holding the DeclStmt
+			names := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				names[i] = name
+			}
+			record(names, n.Values, parents[len(parents)-2], parents[len(parents)-3])
+		}
+		parents = append(parents, n)
+		return true
+	})
+
+	// Assignments are recorded in source order
+	for i := len(all) - 1; i >= 0; i-- {
+		if onEveryPath[i] {
+			return &all[i], all[i+1:]
+		}
+	}
+	return nil, all
+}
+
+// dominates reports whether stmt, held by parent, happens on every path to the call,
+// that is, it is in the same or an enclosing block, or initializes an enclosing statement
+func (c *callContext) dominates(stmt, parent ast.Node) bool {
+	if !slices.Contains(c.stack, parent) {
+		return false
+	}
+	switch parent := parent.(type) {
+	case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+		return true
+	case *ast.IfStmt:
+		return parent.Init == stmt
+	case *ast.SwitchStmt:
+		return parent.Init == stmt
+	case *ast.TypeSwitchStmt:
+		return parent.Init == stmt
+	case *ast.ForStmt:
+		return parent.Init == stmt
+	}
+	return false
+}
+
+// enclosingFuncBody returns the body of the innermost function in stack
+func enclosingFuncBody(stack []ast.Node) *ast.BlockStmt {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl:
+			return n.Body
+		case *ast.FuncLit:
+			return n.Body
+		}
+	}
+	return nil
+}
+
+// allowsGlobalUpdate reports whether expr is &gorm.Session{AllowGlobalUpdate: true}
+func allowsGlobalUpdate(c *callContext, expr ast.Expr) bool {
+	lit := compositeLit(expr)
+	if lit == nil {
+		return false
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "AllowGlobalUpdate" {
+			tv := c.pass.TypesInfo.Types[kv.Value]
+			return tv.Value != nil && tv.Value.Kind() == constant.Bool && constant.BoolVal(tv.Value)
+		}
+	}
+	return false
+}
+
+// hasPrimaryKey reports whether the model or value expr may carry a primary key. Only
+// composite literals are known not to: struct literals without their primary key
+// field, and empty slice or map literals.
+func hasPrimaryKey(c *callContext, expr ast.Expr) bool {
+	lit := compositeLit(expr)
+	if lit == nil {
+		return !c.pass.TypesInfo.Types[expr].IsNil()
+	}
+	switch t := c.pass.TypesInfo.TypeOf(lit).Underlying().(type) {
+	case *types.Struct:
+		return structHasPrimaryKey(c, lit, t)
+	case *types.Map:
+		return false
+	}
+	// Slices of models are deleted by the primary keys of their elements
+	return len(lit.Elts) > 0
+}
+
+// structHasPrimaryKey reports whether the struct literal lit of type st sets its
+// primary key, including within embedded structs like gorm.Model
+func structHasPrimaryKey(c *callContext, lit *ast.CompositeLit, st *types.Struct) bool {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return true // positional literals set every field
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			if field.Name() != key.Name {
+				continue
+			}
+			if isPrimaryKey(st, i) {
+				return true
+			}
+			if embedded, ok := field.Type().Underlying().(*types.Struct); ok && field.Embedded() {
+				if inner := compositeLit(kv.Value); inner != nil && structHasPrimaryKey(c, inner, embedded) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isPrimaryKey reports whether the field i of st is the primary key by GORM conventions
+func isPrimaryKey(st *types.Struct, i int) bool {
+	tag := strings.ToLower(reflect.StructTag(st.Tag(i)).Get("gorm"))
+	return st.Field(i).Name() == "ID" || strings.Contains(tag, "primarykey") || strings.Contains(tag, "primary_key")
+}
+
+// compositeLit returns the composite literal of expr or &expr, if any
+func compositeLit(expr ast.Expr) *ast.CompositeLit {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+	lit, _ := expr.(*ast.CompositeLit)
+	return lit
+}
@@ -0,0 +1,118 @@
+package mustreceive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkContext reports uses of the incoming context after the call on top of stack
+// returned a new one, which loses what the call attached to it, e.g. the parent span
+func checkContext(pass *analysis.Pass, fn *types.Func, config *FuncConfig, stack []ast.Node) {
+	call := stack[len(stack)-1].(*ast.CallExpr)
+	receivers, end := resultReceivers(pass.TypesInfo, stack)
+	if receivers == nil {
+		return
+	}
+
+	// The context passed to the call
+	var incoming *types.Var
+	for _, arg := range call.Args {
+		if ident, ok := ast.Unparen(arg).(*ast.Ident); ok && isContext(pass.TypesInfo.TypeOf(ident)) {
+			incoming, _ = pass.TypesInfo.Uses[ident].(*types.Var)
+			break
+		}
+	}
+	if incoming == nil {
+		return
+	}
+
+	results := fn.Type().(*types.Signature).Results()
+	for i := 0; i < results.Len() && i < len(receivers); i++ {
+		if !isContext(results.At(i).Type()) {
+			continue
+		}
+		ident, ok := receivers[i].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if ident.Name != "_" && pass.TypesInfo.ObjectOf(ident) == incoming {
+			continue // ctx, span = pkg.Func(ctx)
+		}
+
+		block := enclosingBlock(stack)
+		if block == nil {
+			return
+		}
+		stale := staleUses(pass, incoming, block, end)
+		if len(stale) == 0 {
+			continue
+		}
+		line := pass.Fset.Position(call.Pos()).Line
+		if ident.Name == "_" {
+			reportCall(pass, fn, config, ident.Pos(), fmt.Sprintf("%s returns a new context, which is discarded while %s is used afterwards", fn.FullName(), incoming.Name()), []int{i})
+			continue
+		}
+		for _, use := range stale {
+			reportCall(pass, fn, config, use.Pos(), fmt.Sprintf("%s is stale after %s returned %s on line %d, use %s instead", incoming.Name(), fn.FullName(), ident.Name, line, ident.Name), []int{i})
+		}
+	}
+}
+
+// staleUses returns the uses of v in block after pos, until v is assigned again
+func staleUses(pass *analysis.Pass, v *types.Var, block ast.Node, pos token.Pos) []*ast.Ident {
+	var uses []*ast.Ident
+	done := false
+	collect := func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Pos() > pos && pass.TypesInfo.Uses[ident] == v {
+			uses = append(uses, ident)
+		}
+		return true
+	}
+	ast.Inspect(block, func(n ast.Node) bool {
+		if done || n == nil || n.End() <= pos {
+			return !done && n != nil && n.Pos() <= pos && pos < n.End()
+		}
+		if assign, ok := n.(*ast.AssignStmt); ok && assign.Pos() > pos {
+			for _, lhs := range assign.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == v {
+					// The right side is evaluated with the stale context
+					for _, rhs := range assign.Rhs {
+						ast.Inspect(rhs, collect)
+					}
+					done = true
+					return false
+				}
+			}
+		}
+		return collect(n)
+	})
+	return uses
+}
+
+// enclosingBlock returns the innermost block containing the top of stack
+func enclosingBlock(stack []ast.Node) ast.Node {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			return n
+		}
+	}
+	return nil
+}
+
+func isContext(t types.Type) bool {
+	return isNamed(t, "context", "Context")
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
@@ -0,0 +1,105 @@
+package mustreceive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// directivePrefix marks functions and interface methods whose results must be
+// received, optionally followed by the indices of required results: //lint:mustreceive 1
+const directivePrefix = "//lint:mustreceive"
+
+// mustReceiveFact is exported for annotated functions and methods, so that calls
+// from every downstream package are checked
+type mustReceiveFact struct {
+	Results []int
+}
+
+func (*mustReceiveFact) AFact() {}
+
+func (f *mustReceiveFact) String() string {
+	if len(f.Results) == 0 {
+		return "mustreceive"
+	}
+	results := make([]string, len(f.Results))
+	for i, index := range f.Results {
+		results[i] = strconv.Itoa(index)
+	}
+	return "mustreceive " + strings.Join(results, ",")
+}
+
+// exportDirectives exports mustReceiveFact for the annotated function declarations
+// and interface methods of the package
+func exportDirectives(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				exportDirective(pass, n.Doc, n.Name)
+			case *ast.InterfaceType:
+				for _, field := range n.Methods.List {
+					if len(field.Names) == 1 {
+						exportDirective(pass, field.Doc, field.Names[0])
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+func exportDirective(pass *analysis.Pass, doc *ast.CommentGroup, name *ast.Ident) {
+	if doc == nil {
+		return
+	}
+	for _, c := range doc.List {
+		args, ok := strings.CutPrefix(c.Text, directivePrefix)
+		if !ok || (args != "" && args[0] != ' ') {
+			continue
+		}
+		fn, ok := pass.TypesInfo.Defs[name].(*types.Func)
+		if !ok {
+			continue
+		}
+		results, err := parseResults(args, fn.Type().(*types.Signature).Results().Len())
+		if err != nil {
+			reportDirective(pass, name.Pos(), err.Error())
+			continue
+		}
+		pass.ExportObjectFact(fn, &mustReceiveFact{Results: results})
+	}
+}
+
+// parseResults parses the comma-separated result indices of a directive
+func parseResults(args string, n int) ([]int, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("%s on a function without results", directivePrefix)
+	}
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return nil, nil
+	}
+	var results []int
+	for _, arg := range strings.Split(args, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil || index < 0 || index >= n {
+			return nil, fmt.Errorf("invalid result index %q in %s, the function has %d results", strings.TrimSpace(arg), directivePrefix, n)
+		}
+		results = append(results, index)
+	}
+	return results, nil
+}
+
+func reportDirective(pass *analysis.Pass, pos token.Pos, message string) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      pos,
+		Category: "mustreceive",
+		Message:  message,
+	})
+}
@@ -0,0 +1,92 @@
+package mustreceive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// suggestedFixes binds the results of the call statement on top of stack to the
+// configured variables, followed by the configured statement
+func suggestedFixes(pass *analysis.Pass, fn *types.Func, fix *FixConfig, stack []ast.Node) []analysis.SuggestedFix {
+	call := stack[len(stack)-1]
+	stmt, ok := stack[len(stack)-2].(*ast.ExprStmt)
+	if !ok || stmt.X != call {
+		return nil // go, defer and calls within expressions can't be rewritten in place
+	}
+	if len(fix.Names) != fn.Type().(*types.Signature).Results().Len() {
+		return nil
+	}
+
+	// Assign when every variable already exists, declare otherwise. A declaration
+	// reuses the variables of the innermost scope, but would shadow those of outer
+	// scopes, so no fix is offered then.
+	scope := pass.Pkg.Scope().Innermost(call.Pos())
+	if scope == nil {
+		return nil
+	}
+	var named, missing, outer bool
+	for _, name := range fix.Names {
+		if name == "_" {
+			continue
+		}
+		named = true
+		if obj := scope.Lookup(name); obj != nil {
+			if _, ok := obj.(*types.Var); !ok || obj.Pos() > call.Pos() {
+				return nil // neither assignable nor declarable
+			}
+			continue
+		}
+		switch _, obj := scope.LookupParent(name, call.Pos()); obj.(type) {
+		case nil:
+			missing = true
+		case *types.Var:
+			outer = true
+		default:
+			return nil // a declaration would shadow a type, constant or function
+		}
+	}
+	if !named {
+		return nil // assigning every result to _ still discards them
+	}
+	op := "="
+	if missing {
+		if outer {
+			return nil
+		}
+		op = ":="
+	}
+
+	edits := []analysis.TextEdit{{
+		Pos:     call.Pos(),
+		End:     call.Pos(),
+		NewText: []byte(strings.Join(fix.Names, ", ") + " " + op + " "),
+	}}
+	if fix.then != nil {
+		var then strings.Builder
+		if err := fix.then.Execute(&then, struct{ Names []string }{fix.Names}); err != nil {
+			return nil
+		}
+		// Insert on the next line, after any trailing comment. gofmt indents with tabs,
+		// one per column before the statement.
+		file := pass.Fset.File(stmt.End())
+		line := file.Line(stmt.End())
+		if line >= file.LineCount() {
+			return nil
+		}
+		eol := file.LineStart(line+1) - 1
+		indent := strings.Repeat("\t", pass.Fset.Position(stmt.Pos()).Column-1)
+		edits = append(edits, analysis.TextEdit{
+			Pos:     eol,
+			End:     eol,
+			NewText: []byte("\n" + indent + then.String()),
+		})
+	}
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Bind the results to %s", strings.Join(fix.Names, ", ")),
+		TextEdits: edits,
+	}}
+}
@@ -0,0 +1,197 @@
+package mustreceive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// checkCloser reports the call on top of stack if a path from it reaches a return
+// without releasing the result, in the spirit of the lostcancel analyzer
+func checkCloser(pass *analysis.Pass, fn *types.Func, config *FuncConfig, stack []ast.Node) {
+	closer := config.Closer
+	receivers, _ := resultReceivers(pass.TypesInfo, stack)
+	if closer.Result < 0 || closer.Result >= len(receivers) {
+		return
+	}
+	ident, ok := receivers[closer.Result].(*ast.Ident)
+	if !ok {
+		return // stored in a field or an element, released elsewhere
+	}
+	if ident.Name == "_" {
+		reportCall(pass, fn, config, ident.Pos(), fmt.Sprintf("%s result %d must be received and released", fn.FullName(), closer.Result), []int{closer.Result})
+		return
+	}
+	v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	if !ok {
+		return
+	}
+
+	// The receiving statement, past parentheses and conversions, and the function it belongs to
+	parent, _ := receiverParent(pass.TypesInfo, stack)
+	stmt := stack[parent]
+	var g *cfg.CFG
+	var sig *types.Signature
+	cfgs := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
+	for i := len(stack) - 1; i >= 0 && g == nil; i-- {
+		switch node := stack[i].(type) {
+		case *ast.FuncDecl:
+			if node.Pos() > v.Pos() {
+				return // variables of enclosing functions are released there
+			}
+			g = cfgs.FuncDecl(node)
+			sig, _ = pass.TypesInfo.TypeOf(node.Name).(*types.Signature)
+		case *ast.FuncLit:
+			if node.Pos() > v.Pos() {
+				return
+			}
+			g = cfgs.FuncLit(node)
+			sig, _ = pass.TypesInfo.TypeOf(node).(*types.Signature)
+		}
+	}
+	if g == nil || sig == nil {
+		return
+	}
+
+	ret := unreleasedPath(pass, g, stmt, func(n ast.Node) bool {
+		return releases(pass, n, v, closer, sig)
+	})
+	if ret == nil {
+		return
+	}
+	name := closerName(closer, v.Name())
+	reportCall(pass, fn, config, stmt.Pos(), fmt.Sprintf("%s result %d is not released by %s on all paths", fn.FullName(), closer.Result, name), []int{closer.Result})
+	pass.Report(analysis.Diagnostic{
+		Pos:      ret.Pos(),
+		Category: "mustreceive",
+		Message:  fmt.Sprintf("this return statement may be reached without calling %s for the %s var defined on line %d", name, v.Name(), pass.Fset.Position(stmt.Pos()).Line),
+		URL:      config.URL,
+	})
+}
+
+// unreleasedPath finds a path through the CFG from stmt to a return statement on which
+// no node releases the result, and returns that return statement, which may be synthetic
+func unreleasedPath(pass *analysis.Pass, g *cfg.CFG, stmt ast.Node, released func(ast.Node) bool) *ast.ReturnStmt {
+	anyReleased := func(nodes []ast.Node) bool {
+		for _, n := range nodes {
+			if released(n) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Find the defining block, plus the rest of its statements
+	var defblock *cfg.Block
+	var rest []ast.Node
+outer:
+	for _, b := range g.Blocks {
+		for i, n := range b.Nodes {
+			if n == stmt {
+				defblock, rest = b, b.Nodes[i+1:]
+				break outer
+			}
+		}
+	}
+	if defblock == nil || anyReleased(rest) {
+		return nil
+	}
+	if ret := defblock.Return(); ret != nil {
+		return ret
+	}
+
+	// Search the CFG depth-first for a path to a return block that never releases
+	seen := make(map[*cfg.Block]bool)
+	var search func(blocks []*cfg.Block) *ast.ReturnStmt
+	search = func(blocks []*cfg.Block) *ast.ReturnStmt {
+		for _, b := range blocks {
+			if seen[b] {
+				continue
+			}
+			seen[b] = true
+			if anyReleased(b.Nodes) {
+				continue
+			}
+			if ret := b.Return(); ret != nil {
+				return ret
+			}
+			if ret := search(b.Succs); ret != nil {
+				return ret
+			}
+		}
+		return nil
+	}
+	return search(defblock.Succs)
+}
+
+// releases reports whether node calls the closer on v, including in deferred
+// function literals, or hands v over to the caller by returning it
+func releases(pass *analysis.Pass, node ast.Node, v *types.Var, closer *CloserConfig, sig *types.Signature) bool {
+	isV := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[ident] == v
+	}
+
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			switch {
+			case closer.Method != "":
+				if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == closer.Method && isV(sel.X) {
+					found = true
+				}
+			case closer.Func != "":
+				callee, ok := typeutil.Callee(pass.TypesInfo, n).(*types.Func)
+				if ok && callee.Pkg() != nil && callee.Pkg().Path() == closer.Package && callee.Name() == closer.Func {
+					for _, arg := range n.Args {
+						if isV(arg) {
+							found = true
+						}
+					}
+				}
+			default:
+				if isV(n.Fun) {
+					found = true
+				}
+			}
+		case *ast.ReturnStmt:
+			if n.Results == nil && isResult(sig, v) {
+				found = true // naked return of a named result
+			}
+			for _, result := range n.Results {
+				if isV(result) {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func isResult(sig *types.Signature, v *types.Var) bool {
+	results := sig.Results()
+	for i := 0; i < results.Len(); i++ {
+		if results.At(i) == v {
+			return true
+		}
+	}
+	return false
+}
+
+// closerName describes the closer call for v in diagnostics
+func closerName(closer *CloserConfig, v string) string {
+	switch {
+	case closer.Method != "":
+		return v + "." + closer.Method + "()"
+	case closer.Func != "":
+		return closer.Package + "." + closer.Func
+	}
+	return v + "()"
+}
@@ -1,11 +1,22 @@
 package mustreceive
 
 import (
+	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/golangci/plugin-module-register/register"
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 func init() {
@@ -13,16 +24,94 @@ func init() {
 }
 
 type FuncConfig struct {
-	Package string `json:"package"`
-	Func    string `json:"func"`
+	Package  string `json:"package"`
+	Receiver string `json:"receiver"` // Receiver type name for methods, e.g. "*DB" or "Tracer", including interfaces
+	Func     string `json:"func"`
+
+	// Results lists the indices of the results that must be bound to a non-blank
+	// variable and used afterwards, e.g. [1] for the span of logtracing.StartSpan
+	Results []int `json:"results"`
+
+	// Closer declares the call that must release a result on every path
+	Closer *CloserConfig `json:"closer"`
+
+	// Message replaces the diagnostic messages with a text/template explaining why,
+	// e.g. "{{.FullName}} starts a span, result {{.Results}} must be ended".
+	// Fields: Package, Func, FullName and Results, the indices of the missing results.
+	Message string `json:"message"`
+	URL     string `json:"url"` // Documentation link attached to the diagnostics
+
+	// Fix configures the suggested fix for calls in statement position
+	Fix *FixConfig `json:"fix"`
+
+	message *template.Template // Parsed Message, set by BuildAnalyzers
+}
+
+// FixConfig configures the suggested fix binding the results of a call to variables.
+// Existing variables are assigned with =, otherwise they are declared with :=.
+type FixConfig struct {
+	Names []string `json:"names"` // Names of the variables, one per result, "_" to discard, e.g. ["ctx", "span"]
+	Then  string   `json:"then"`  // text/template of a statement inserted after the call, e.g. "defer {{index .Names 1}}.End()"
+
+	then *template.Template // Parsed Then, set by BuildAnalyzers
+}
+
+// CloserConfig declares how a received result is released, like span.End(),
+// cancel() or logtracing.EndSpan(ctx, err). Returning the result hands it over to the caller.
+type CloserConfig struct {
+	Result  int    `json:"result"`  // Index of the result to release
+	Method  string `json:"method"`  // Method called on the result, e.g. "End"
+	Package string `json:"package"` // Package of Func
+	Func    string `json:"func"`    // Function taking the result as an argument, e.g. "EndSpan"
+
+	// When both Method and Func are empty, the result itself is called, like cancel()
+}
+
+// funcKey identifies a configured function or method
+type funcKey struct {
+	pkg, recv, name string
 }
 
 type Settings struct {
 	MustReceiveFuncs []FuncConfig `json:"must-receive-funcs"`
+
+	// Presets enables built-in catalogues of well-known functions: "stdlib", "pkgerrors", "gorm"
+	// and "otel". Entries of MustReceiveFuncs override preset entries for the same function.
+	Presets []string `json:"presets"`
+
+	// Receivers lists the expressions, besides assignments, that count as receiving
+	// the results: "return", "argument" and "composite". All of them by default.
+	Receivers []string `json:"receivers"`
+
+	// Purity reports discarded results of calls to functions inferred as side-effect free
+	Purity PuritySettings `json:"purity"`
+
+	// StaleContext reports uses of the context passed to a configured function after it
+	// returned a new one, e.g. ctx used after "newCtx, span := logtracing.StartSpan(ctx, ...)"
+	StaleContext bool `json:"stale-context"`
 }
 
+// PuritySettings configures the opt-in inference of pure functions: functions that don't
+// write through pointers they didn't allocate, don't write globals, and don't use channels,
+// goroutines or I/O. Calls to them whose results are all discarded are reported.
+type PuritySettings struct {
+	Enabled       bool     `json:"enabled"`
+	MinConfidence string   `json:"min-confidence"` // "high" (default) or "low", see the purity levels
+	Allow         []string `json:"allow"`          // Full names of functions never reported, e.g. "(*bytes.Buffer).Len"
+}
+
+// Receiver kinds, see Settings.Receivers
+const (
+	ReceiverReturn    = "return"    // return pkg.Func()
+	ReceiverArgument  = "argument"  // other(pkg.Func())
+	ReceiverComposite = "composite" // T{Field: pkg.Func()}
+)
+
+var receiverKinds = []string{ReceiverReturn, ReceiverArgument, ReceiverComposite}
+
 type Linter struct {
 	settings Settings
+	funcs    map[funcKey]*FuncConfig // Set by BuildAnalyzers
 }
 
 func New(settings any) (register.LinterPlugin, error) {
@@ -31,150 +120,302 @@ func New(settings any) (register.LinterPlugin, error) {
 		return nil, err
 	}
 
+	for _, name := range s.Presets {
+		if _, ok := presets[name]; !ok {
+			return nil, fmt.Errorf("unknown preset %q", name)
+		}
+	}
+	if c := s.Purity.MinConfidence; c != "" && confidences[c] == impure {
+		return nil, fmt.Errorf("unknown min-confidence %q, must be low or high", c)
+	}
+	for _, kind := range s.Receivers {
+		if !slices.Contains(receiverKinds, kind) {
+			return nil, fmt.Errorf("unknown receiver %q, must be one of %s", kind, strings.Join(receiverKinds, ", "))
+		}
+	}
+
 	return &Linter{settings: s}, nil
 }
 
 func (l *Linter) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	funcs, err := l.configs()
+	if err != nil {
+		return nil, err
+	}
+	l.funcs = funcs
+
+	var requires []*analysis.Analyzer
+	for _, f := range funcs {
+		if f.Closer != nil {
+			// Closers are checked along the control flow graph
+			requires = append(requires, ctrlflow.Analyzer)
+			break
+		}
+	}
+	if l.settings.Purity.Enabled {
+		requires = append(requires, buildssa.Analyzer)
+	}
+
 	return []*analysis.Analyzer{
 		{
 			Name: "mustreceive",
 			Doc:  "Check if function return values are properly received",
 			Run:  l.run,
+
+			Requires:  append([]*analysis.Analyzer{inspect.Analyzer}, requires...),
+			FactTypes: []analysis.Fact{new(mustReceiveFact), new(purityFact)},
 		},
 	}, nil
 }
 
 func (l *Linter) GetLoadMode() string {
-	return register.LoadModeSyntax
+	return register.LoadModeTypesInfo
 }
 
-func (l *Linter) run(pass *analysis.Pass) (any, error) {
-	// Create a map for faster lookup
-	mustReceiveFuncs := make(map[string]map[string]bool)
-	for _, f := range l.settings.MustReceiveFuncs {
-		if _, ok := mustReceiveFuncs[f.Package]; !ok {
-			mustReceiveFuncs[f.Package] = make(map[string]bool)
-		}
-		mustReceiveFuncs[f.Package][f.Func] = true
-	}
-
-	// Inspect each file
-	for _, file := range pass.Files {
-		// Build import path to package name mapping
-		importMap := make(map[string]string)
-		dotImports := make(map[string]bool) // Track packages imported with dot import
-		for _, imp := range file.Imports {
-			path := strings.Trim(imp.Path.Value, "\"")
-			if imp.Name != nil {
-				if imp.Name.Name == "." {
-					// Handle dot imports like: import . "github.com/theplant/appkit/logtracing"
-					dotImports[path] = true
-				} else {
-					// Handle named imports like: import log "github.com/theplant/appkit/logtracing"
-					importMap[imp.Name.Name] = path
-				}
-			} else {
-				// Handle regular imports like: import "github.com/theplant/appkit/logtracing"
-				parts := strings.Split(path, "/")
-				importMap[parts[len(parts)-1]] = path
+// configs indexes the configured functions, after those of the enabled presets
+// so that they override them, and parses their templates
+func (l *Linter) configs() (map[funcKey]*FuncConfig, error) {
+	var funcs []FuncConfig
+	for _, name := range l.settings.Presets {
+		funcs = append(funcs, presets[name]...)
+	}
+	funcs = append(funcs, l.settings.MustReceiveFuncs...)
+
+	configs := make(map[funcKey]*FuncConfig, len(funcs))
+	for i := range funcs {
+		f := &funcs[i]
+		if f.Message != "" {
+			tmpl, err := template.New("message").Parse(f.Message)
+			if err != nil {
+				return nil, fmt.Errorf("invalid message template for %s.%s: %w", f.Package, f.Func, err)
 			}
+			f.message = tmpl
 		}
+		if f.Fix != nil && f.Fix.Then != "" {
+			tmpl, err := template.New("then").Parse(f.Fix.Then)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fix template for %s.%s: %w", f.Package, f.Func, err)
+			}
+			// Copied to leave the settings untouched
+			fix := *f.Fix
+			fix.then = tmpl
+			f.Fix = &fix
+		}
+		// Pointer and value receivers share the method set of the named type
+		configs[funcKey{pkg: f.Package, recv: strings.TrimPrefix(f.Receiver, "*"), name: f.Func}] = f
+	}
+	return configs, nil
+}
 
-		// Stack to keep track of parent nodes
-		var stack []ast.Node
+func (l *Linter) run(pass *analysis.Pass) (any, error) {
+	uses := newUseIndex(pass)
 
-		ast.Inspect(file, func(n ast.Node) bool {
-			if n == nil {
-				// Pop the stack when we leave a node
-				if len(stack) > 0 {
-					stack = stack[:len(stack)-1]
-				}
-				return true
-			}
+	// Annotated functions of this package are checked within the package as well
+	exportDirectives(pass)
 
-			// Push the current node onto the stack
-			stack = append(stack, n)
+	var purities map[*types.Func]purityFact
+	if l.settings.Purity.Enabled {
+		purities = inferPurity(pass)
+	}
 
-			// Check if it's a function call
-			call, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{(*ast.CallExpr)(nil)}
 
-			// Get package and function name
-			var pkgName, funcName string
-			switch fun := call.Fun.(type) {
-			case *ast.Ident:
-				funcName = fun.Name
-			case *ast.SelectorExpr:
-				if pkg, ok := fun.X.(*ast.Ident); ok {
-					pkgName = pkg.Name
-					funcName = fun.Sel.Name
-				}
-			}
+	// The stack holds the enclosing nodes of each call, with the call on top
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
 
-			// Check if this function requires return value reception
-			if pkgName != "" && funcName != "" {
-				// Handle normal imports with package qualifier
-				if fullPkgPath, ok := importMap[pkgName]; ok {
-					// Check if this package and function are in our list
-					if pkgFuncs, ok := mustReceiveFuncs[fullPkgPath]; ok && pkgFuncs[funcName] {
-						// Check if the call is part of an assignment or variable declaration
-						if !hasReturnValueReceiver(stack) {
-							pass.Report(analysis.Diagnostic{
-								Pos:      call.Pos(),
-								Category: "mustreceive",
-								Message:  fullPkgPath + "." + funcName + " must receive its return value",
-							})
-						}
-					}
+		// Resolve the called function or method, whether it's imported by name,
+		// alias or dot import, or called on a chained expression
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil {
+			return true
+		}
+		fn = fn.Origin()
+
+		// Check if this function requires return value reception
+		if config := mergeFact(pass, fn, l.funcs[funcKeyOf(fn)]); config != nil {
+			// Check if the call is part of an assignment or variable declaration
+			if !l.hasReturnValueReceiver(pass.TypesInfo, stack) {
+				all := make([]int, fn.Type().(*types.Signature).Results().Len())
+				for i := range all {
+					all[i] = i
+				}
+				diag := callDiagnostic(fn, config, call.Pos(), fn.FullName()+notReceivedReason(stack), all)
+				if config.Fix != nil {
+					diag.SuggestedFixes = suggestedFixes(pass, fn, config.Fix, stack)
+				}
+				pass.Report(diag)
+			} else {
+				if len(config.Results) > 0 {
+					checkResults(pass, fn, config, stack, uses)
+				}
+				if config.Closer != nil {
+					checkCloser(pass, fn, config, stack)
 				}
-			} else if funcName != "" {
-				// Handle dot imports (functions called without package qualifier)
-				for dotImportPath := range dotImports {
-					if pkgFuncs, ok := mustReceiveFuncs[dotImportPath]; ok && pkgFuncs[funcName] {
-						// Check if the call is part of an assignment or variable declaration
-						if !hasReturnValueReceiver(stack) {
-							pass.Report(analysis.Diagnostic{
-								Pos:      call.Pos(),
-								Category: "mustreceive",
-								Message:  dotImportPath + "." + funcName + " must receive its return value",
-							})
-						}
-					}
+				if l.settings.StaleContext {
+					checkContext(pass, fn, config, stack)
 				}
 			}
-			return true
-		})
-	}
+		} else if purities != nil {
+			l.checkPurity(pass, fn, purities, stack)
+		}
+		return true
+	})
 	return nil, nil
 }
 
+// messageData is the data of FuncConfig.Message templates
+type messageData struct {
+	Package  string
+	Func     string
+	FullName string
+	Results  string
+}
+
+// reportCall reports a diagnostic about a call to fn, with the configured message and URL if any
+func reportCall(pass *analysis.Pass, fn *types.Func, config *FuncConfig, pos token.Pos, message string, results []int) {
+	pass.Report(callDiagnostic(fn, config, pos, message, results))
+}
+
+func callDiagnostic(fn *types.Func, config *FuncConfig, pos token.Pos, message string, results []int) analysis.Diagnostic {
+	diag := analysis.Diagnostic{
+		Pos:      pos,
+		Category: "mustreceive",
+		Message:  message,
+		URL:      config.URL,
+	}
+	if config.message != nil {
+		indices := make([]string, len(results))
+		for i, index := range results {
+			indices[i] = strconv.Itoa(index)
+		}
+		data := messageData{
+			Package:  fn.Pkg().Path(),
+			Func:     fn.Name(),
+			FullName: fn.FullName(),
+			Results:  strings.Join(indices, ", "),
+		}
+		var buf strings.Builder
+		if config.message.Execute(&buf, data) == nil {
+			diag.Message = buf.String()
+		}
+	}
+	return diag
+}
+
+// mergeFact merges the //lint:mustreceive annotation of fn into its configuration, if any
+func mergeFact(pass *analysis.Pass, fn *types.Func, config *FuncConfig) *FuncConfig {
+	var fact mustReceiveFact
+	if !pass.ImportObjectFact(fn, &fact) {
+		return config
+	}
+	if config == nil {
+		return &FuncConfig{Package: fn.Pkg().Path(), Func: fn.Name(), Results: fact.Results}
+	}
+	merged := *config
+	merged.Results = slices.Clone(config.Results)
+	for _, index := range fact.Results {
+		if !slices.Contains(merged.Results, index) {
+			merged.Results = append(merged.Results, index)
+		}
+	}
+	return &merged
+}
+
+// funcKeyOf returns the lookup key of a function, or of a method by its receiver type name
+func funcKeyOf(fn *types.Func) funcKey {
+	key := funcKey{pkg: fn.Pkg().Path(), name: fn.Name()}
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			key.recv = named.Obj().Name()
+		} else {
+			key.recv = "?" // methods of unnamed interfaces can't be configured
+		}
+	}
+	return key
+}
+
 // hasReturnValueReceiver checks if the function call is properly receiving its return value
-func hasReturnValueReceiver(stack []ast.Node) bool {
-	// Look for assignment or declaration in the stack
-	for i := len(stack) - 1; i >= 0; i-- {
-		switch n := stack[i].(type) {
-		case *ast.AssignStmt:
-			// Check if the call is on the right side of the assignment
-			for _, rhs := range n.Rhs {
-				if rhs == stack[len(stack)-1] {
-					return true
-				}
+func (l *Linter) hasReturnValueReceiver(info *types.Info, stack []ast.Node) bool {
+	i, child := receiverParent(info, stack)
+	if i < 0 {
+		return false
+	}
+
+	switch n := stack[i].(type) {
+	case *ast.AssignStmt, *ast.ValueSpec:
+		// Assigning every result to the blank identifier discards them
+		receivers, _ := resultReceivers(info, stack)
+		for _, expr := range receivers {
+			if ident, ok := expr.(*ast.Ident); !ok || ident.Name != "_" {
+				return true
 			}
-		case *ast.DeclStmt:
-			if genDecl, ok := n.Decl.(*ast.GenDecl); ok {
-				for _, spec := range genDecl.Specs {
-					if valueSpec, ok := spec.(*ast.ValueSpec); ok {
-						for _, value := range valueSpec.Values {
-							if value == stack[len(stack)-1] {
-								return true
-							}
-						}
-					}
-				}
+		}
+	case *ast.ReturnStmt:
+		return l.receiverEnabled(ReceiverReturn)
+	case *ast.CallExpr:
+		// Passed as an argument, not called itself
+		return n.Fun != child && l.receiverEnabled(ReceiverArgument)
+	case *ast.CompositeLit:
+		return l.receiverEnabled(ReceiverComposite)
+	case *ast.KeyValueExpr:
+		// A field or element of a composite literal, not its key
+		if n.Value == child && i > 0 {
+			if _, ok := stack[i-1].(*ast.CompositeLit); ok {
+				return l.receiverEnabled(ReceiverComposite)
 			}
 		}
 	}
+	// Statement-position, go and defer calls discard their results, as do calls
+	// whose result is only an operand, e.g. of a selector or another call
 	return false
 }
+
+// receiverParent returns the index in stack of the node receiving the value of the
+// call on top of stack, and its child holding the call. Parentheses and conversions
+// pass the value through, so "(pkg.Func())" and "T(pkg.Func())" are skipped.
+func receiverParent(info *types.Info, stack []ast.Node) (int, ast.Node) {
+	child := stack[len(stack)-1]
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.ParenExpr:
+		case *ast.CallExpr:
+			if n.Fun == child || !info.Types[n.Fun].IsType() {
+				return i, child
+			}
+		default:
+			return i, child
+		}
+		child = stack[i]
+	}
+	return -1, nil
+}
+
+// notReceivedReason explains why the call on top of stack does not receive its return value
+func notReceivedReason(stack []ast.Node) string {
+	call := stack[len(stack)-1]
+	switch n := stack[len(stack)-2].(type) {
+	case *ast.GoStmt:
+		if n.Call == call {
+			return " is called in a go statement, which discards its return value"
+		}
+	case *ast.DeferStmt:
+		if n.Call == call {
+			return " is called in a defer statement, which discards its return value"
+		}
+	}
+	return " must receive its return value"
+}
+
+// receiverEnabled reports whether kind counts as receiving, all kinds do by default
+func (l *Linter) receiverEnabled(kind string) bool {
+	return l.settings.Receivers == nil || slices.Contains(l.settings.Receivers, kind)
+}
@@ -14,6 +14,29 @@ func TestMustReceiveLinter(t *testing.T) {
 				Package: "github.com/theplant/appkit/logtracing",
 				Func:    "StartSpan",
 			},
+			{
+				Package: "testdata/tracing/v2",
+				Func:    "Start",
+			},
+			{
+				Package: "testdata/tracing/v2",
+				Func:    "NewSpan",
+			},
+			{
+				Package:  "database/sql",
+				Receiver: "*DB",
+				Func:     "BeginTx",
+			},
+			{
+				Package:  "testdata/tracing/v2",
+				Receiver: "*Tracer",
+				Func:     "Start",
+			},
+			{
+				Package:  "testdata/tracing/v2",
+				Receiver: "Starter",
+				Func:     "Start",
+			},
 		},
 	}
 
@@ -33,3 +56,260 @@ func TestMustReceiveLinter(t *testing.T) {
 	// Run the test using analysistest with go.mod support
 	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/testpkg")
 }
+
+func TestRequiredResults(t *testing.T) {
+	settings := Settings{
+		MustReceiveFuncs: []FuncConfig{
+			{
+				Package: "github.com/theplant/appkit/logtracing",
+				Func:    "StartSpan",
+				Results: []int{1},
+			},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/results")
+}
+
+func TestLifecycle(t *testing.T) {
+	settings := Settings{
+		MustReceiveFuncs: []FuncConfig{
+			{
+				Package: "github.com/theplant/appkit/logtracing",
+				Func:    "StartSpan",
+				Closer:  &CloserConfig{Result: 1, Method: "End"},
+			},
+			{
+				Package: "context",
+				Func:    "WithCancel",
+				Closer:  &CloserConfig{Result: 1},
+			},
+			{
+				Package:  "testdata/tracing/v2",
+				Receiver: "*Tracer",
+				Func:     "Start",
+				Closer:   &CloserConfig{Result: 0, Package: "testdata/tracing/v2", Func: "Finish"},
+			},
+			{
+				Package: "testdata/tracing/v2",
+				Func:    "NewSpan",
+				Closer:  &CloserConfig{Result: 0, Method: "End"},
+			},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/lifecycle")
+}
+
+func TestReceiverKinds(t *testing.T) {
+	settings := Settings{
+		MustReceiveFuncs: []FuncConfig{
+			{
+				Package: "testdata/tracing/v2",
+				Func:    "NewSpan",
+			},
+		},
+		Receivers: []string{ReceiverReturn},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/receivers")
+}
+
+func TestPresets(t *testing.T) {
+	settings := Settings{
+		Presets: []string{PresetStdlib},
+		MustReceiveFuncs: []FuncConfig{
+			{
+				Package: "github.com/theplant/appkit/logtracing",
+				Func:    "StartSpan",
+			},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/presets")
+}
+
+func TestPresetPackages(t *testing.T) {
+	// The packages are stubbed in testdata/stubs
+	linter := &Linter{settings: Settings{Presets: []string{PresetPkgErrors, PresetGorm, PresetOtel}}}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/presetstubs")
+}
+
+func TestInvalidSettings(t *testing.T) {
+	for name, settings := range map[string]map[string]any{
+		"unknown preset":     {"presets": []string{"kubernetes"}},
+		"unknown receiver":   {"receivers": []string{"channel"}},
+		"unknown confidence": {"purity": map[string]any{"min-confidence": "certain"}},
+	} {
+		if _, err := New(settings); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestInvalidTemplates(t *testing.T) {
+	for name, config := range map[string]FuncConfig{
+		"message": {Package: "context", Func: "WithCancel", Message: "{{.Func"},
+		"fix":     {Package: "context", Func: "WithCancel", Fix: &FixConfig{Names: []string{"ctx", "cancel"}, Then: "defer {{index .Names"}},
+	} {
+		linter := &Linter{settings: Settings{MustReceiveFuncs: []FuncConfig{config}}}
+
+		// Templates are parsed once, when building the analyzer
+		if _, err := linter.BuildAnalyzers(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	linter := &Linter{}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/annotated/...")
+}
+
+func TestPurity(t *testing.T) {
+	settings := Settings{
+		Purity: PuritySettings{
+			Enabled:       true,
+			MinConfidence: "low",
+			Allow:         []string{"strings.HasPrefix"},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/purity")
+}
+
+func TestPurityDefaults(t *testing.T) {
+	linter := &Linter{settings: Settings{Purity: PuritySettings{Enabled: true}}}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/puritydefault")
+}
+
+func TestMessages(t *testing.T) {
+	const url = "https://example.com/docs/tracing"
+	settings := Settings{
+		MustReceiveFuncs: []FuncConfig{
+			{
+				Package: "github.com/theplant/appkit/logtracing",
+				Func:    "StartSpan",
+				Results: []int{1},
+				Message: "{{.Func}} starts a span, results {{.Results}} must be received, see the tracing guide",
+				URL:     url,
+			},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	for _, result := range analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/messages") {
+		for _, diag := range result.Diagnostics {
+			if diag.URL != url {
+				t.Errorf("Expected URL %q, got %q", url, diag.URL)
+			}
+		}
+	}
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	settings := Settings{
+		MustReceiveFuncs: []FuncConfig{
+			{
+				Package: "github.com/theplant/appkit/logtracing",
+				Func:    "StartSpan",
+				Fix:     &FixConfig{Names: []string{"ctx", "span"}, Then: "defer {{index .Names 1}}.End()"},
+			},
+			{
+				Package: "context",
+				Func:    "WithCancel",
+				Fix:     &FixConfig{Names: []string{"ctx", "cancel"}, Then: "defer {{index .Names 1}}()"},
+			},
+		},
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	// Also verifies the suggested fixes against the .golden files
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzers[0], "testdata/fixes")
+}
+
+func TestStaleContext(t *testing.T) {
+	settings := Settings{
+		MustReceiveFuncs: []FuncConfig{
+			{
+				Package: "github.com/theplant/appkit/logtracing",
+				Func:    "StartSpan",
+			},
+		},
+		StaleContext: true,
+	}
+
+	linter := &Linter{settings: settings}
+
+	analyzers, err := linter.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("Failed to build analyzers: %v", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), analyzers[0], "testdata/stalecontext")
+}
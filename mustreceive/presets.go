@@ -0,0 +1,58 @@
+package mustreceive
+
+// Preset names, see Settings.Presets
+const (
+	PresetStdlib    = "stdlib"
+	PresetPkgErrors = "pkgerrors"
+	PresetGorm      = "gorm"
+	PresetOtel      = "otel"
+)
+
+// presets is the built-in catalogue of well-known functions whose results must be received
+var presets = map[string][]FuncConfig{
+	PresetStdlib: concat(
+		funcs("context", "WithCancel", "WithCancelCause", "WithTimeout", "WithTimeoutCause", "WithDeadline", "WithDeadlineCause", "WithValue", "WithoutCancel"),
+		funcs("errors", "New", "Join"),
+		funcs("fmt", "Errorf", "Sprint", "Sprintf", "Sprintln"),
+		funcs("time", "NewTimer", "NewTicker"),
+		funcs("strings", "TrimSpace", "Trim", "TrimLeft", "TrimRight", "TrimPrefix", "TrimSuffix", "ToLower", "ToUpper", "Replace", "ReplaceAll", "Repeat", "Split", "Join"),
+		funcs("bytes", "TrimSpace", "Trim", "TrimLeft", "TrimRight", "TrimPrefix", "TrimSuffix", "ToLower", "ToUpper", "Replace", "ReplaceAll", "Repeat", "Split", "Join", "Clone"),
+		funcs("slices", "Insert", "Delete", "DeleteFunc", "Replace", "Compact", "CompactFunc", "Grow", "Clip", "Clone", "Concat"),
+		funcs("maps", "Clone"),
+		methods("database/sql", "*DB", "Begin", "BeginTx", "Query", "QueryContext", "Conn"),
+		methods("database/sql", "*Tx", "Query", "QueryContext", "Prepare", "PrepareContext"),
+	),
+	PresetPkgErrors: funcs("github.com/pkg/errors", "New", "Errorf", "Wrap", "Wrapf", "WithStack", "WithMessage", "WithMessagef"),
+	PresetGorm: methods("gorm.io/gorm", "*DB",
+		// Chain methods return a new statement instead of modifying the receiver
+		"Model", "Table", "Select", "Omit", "Where", "Not", "Or", "Joins", "InnerJoins", "Group", "Having",
+		"Order", "Limit", "Offset", "Scopes", "Preload", "Distinct", "Unscoped", "Clauses", "Attrs", "Assign",
+		"Session", "WithContext", "Debug", "Begin",
+	),
+	PresetOtel: concat(
+		funcs("go.opentelemetry.io/otel", "Tracer"),
+		methods("go.opentelemetry.io/otel/trace", "Tracer", "Start"),
+		methods("go.opentelemetry.io/otel/trace", "TracerProvider", "Tracer"),
+		funcs("go.opentelemetry.io/otel/trace", "ContextWithSpan", "ContextWithSpanContext"),
+	),
+}
+
+func funcs(pkg string, names ...string) []FuncConfig {
+	return methods(pkg, "", names...)
+}
+
+func methods(pkg, recv string, names ...string) []FuncConfig {
+	configs := make([]FuncConfig, len(names))
+	for i, name := range names {
+		configs[i] = FuncConfig{Package: pkg, Receiver: recv, Func: name}
+	}
+	return configs
+}
+
+func concat(lists ...[]FuncConfig) []FuncConfig {
+	var configs []FuncConfig
+	for _, list := range lists {
+		configs = append(configs, list...)
+	}
+	return configs
+}
@@ -0,0 +1,408 @@
+package mustreceive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+)
+
+// purity is the inferred side-effect freedom of a function, ordered by confidence
+type purity int
+
+const (
+	impure purity = iota
+	// pureLow functions have no side effects of their own, but call function values,
+	// interface methods or functions without Go bodies that are assumed to be pure
+	pureLow
+	// pureHigh functions have no side effects and only call pure functions, including
+	// the functions passed to the parameters they call, see purityFact.Params
+	pureHigh
+)
+
+func (p purity) String() string {
+	switch p {
+	case pureLow:
+		return "low"
+	case pureHigh:
+		return "high"
+	}
+	return "none"
+}
+
+// Confidence levels, see PuritySettings.MinConfidence
+var confidences = map[string]purity{
+	"low":  pureLow,
+	"high": pureHigh,
+}
+
+// purityFact is exported for functions inferred as pure, so that calls from
+// downstream packages are checked
+type purityFact struct {
+	Purity purity
+
+	// Params lists the indices of the function parameters that are called, excluding
+	// the receiver. The purity holds if the functions passed to them are pure, like
+	// unicode.IsSpace passed to strings.TrimFunc, which is decided by each call.
+	Params []int
+}
+
+func (*purityFact) AFact() {}
+
+func (f *purityFact) String() string {
+	if len(f.Params) > 0 {
+		return fmt.Sprintf("pure(%s, calls %v)", f.Purity, f.Params)
+	}
+	return "pure(" + f.Purity.String() + ")"
+}
+
+// Packages whose functions without Go bodies, implemented in assembly or linked from
+// the runtime, are known to be pure or to have side effects
+var (
+	pureBodylessPackages   = []string{"internal/bytealg", "math"}
+	impureBodylessPackages = []string{"runtime", "internal/runtime", "syscall", "internal/syscall", "internal/poll", "os", "time", "sync", "golang.org/x/sys"}
+)
+
+// purityAnalysis infers the purity of the functions of a package from their SSA form.
+// A pure function doesn't write through pointers it didn't allocate, doesn't write
+// globals, doesn't use channels or goroutines, doesn't panic and only calls pure functions.
+//
+// Recursive functions are resolved per strongly connected component of the call graph,
+// with Tarjan's algorithm: every function of a cycle calls every other one, so they
+// all get the purity of the least pure one, once the whole cycle is analyzed.
+type purityAnalysis struct {
+	pass   *analysis.Pass
+	memo   map[*ssa.Function]purityFact
+	index  map[*ssa.Function]int   // position in stack of functions being analyzed
+	params map[*ssa.Function][]int // called parameters of functions being analyzed
+	stack  []*ssa.Function
+}
+
+// inferPurity infers the purity of the functions of the package, exports purityFact
+// for the pure ones and returns the purity of declared functions
+func inferPurity(pass *analysis.Pass) map[*types.Func]purityFact {
+	a := &purityAnalysis{
+		pass:   pass,
+		memo:   make(map[*ssa.Function]purityFact),
+		index:  make(map[*ssa.Function]int),
+		params: make(map[*ssa.Function][]int),
+	}
+	result := make(map[*types.Func]purityFact)
+	for _, fn := range pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA).SrcFuncs {
+		obj, ok := fn.Object().(*types.Func)
+		if !ok {
+			continue // function literals are analyzed with their callers
+		}
+		f, _ := a.of(fn)
+		result[obj] = f
+		if f.Purity > impure {
+			pass.ExportObjectFact(obj, &f)
+		}
+	}
+	return result
+}
+
+// of returns the purity of fn, and the lowest stack position of the functions being
+// analyzed that it depends on, or len(stack) if none. The purity is final only if
+// no function being analyzed is depended on.
+func (a *purityAnalysis) of(fn *ssa.Function) (purityFact, int) {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	if f, ok := a.memo[fn]; ok {
+		return f, len(a.stack)
+	}
+
+	// Functions of other packages are known through facts
+	if obj, ok := fn.Object().(*types.Func); ok && obj.Pkg() != nil && obj.Pkg() != a.pass.Pkg {
+		var fact purityFact
+		if a.pass.ImportObjectFact(obj.Origin(), &fact) {
+			return fact, len(a.stack)
+		}
+		return purityFact{Purity: impure}, len(a.stack)
+	}
+
+	if len(fn.Blocks) == 0 {
+		return purityFact{Purity: bodylessPurity(fn)}, len(a.stack)
+	}
+	if i, ok := a.index[fn]; ok {
+		return purityFact{Purity: pureHigh}, i // recursion, the root of the cycle decides
+	}
+
+	i := len(a.stack)
+	a.index[fn] = i
+	a.stack = append(a.stack, fn)
+	p, low := a.analyze(fn)
+	if low < i {
+		// Part of a cycle whose root is still being analyzed, p flows into it
+		return purityFact{Purity: p, Params: a.params[fn]}, low
+	}
+
+	// fn is the root of its cycle, which gets the purity of the least pure function
+	for _, member := range a.stack[i:] {
+		a.memo[member] = purityFact{Purity: p, Params: a.params[member]}
+		delete(a.index, member)
+		delete(a.params, member)
+	}
+	a.stack = a.stack[:i]
+	return a.memo[fn], len(a.stack)
+}
+
+func bodylessPurity(fn *ssa.Function) purity {
+	if fn.Pkg == nil {
+		return pureLow
+	}
+	path := fn.Pkg.Pkg.Path()
+	inPackages := func(prefixes []string) bool {
+		return slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return path == prefix || strings.HasPrefix(path, prefix+"/")
+		})
+	}
+	switch {
+	case inPackages(pureBodylessPackages):
+		return pureHigh
+	case inPackages(impureBodylessPackages):
+		return impure
+	}
+	return pureLow
+}
+
+func (a *purityAnalysis) analyze(fn *ssa.Function) (purity, int) {
+	p, low := pureHigh, len(a.stack)
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			switch instr := instr.(type) {
+			case *ssa.Store:
+				if !isFresh(instr.Addr, nil) {
+					return impure, low
+				}
+			case *ssa.MapUpdate:
+				if !isFresh(instr.Map, nil) {
+					return impure, low
+				}
+			case *ssa.Send, *ssa.Select, *ssa.Go, *ssa.Panic:
+				return impure, low
+			case *ssa.UnOp:
+				if instr.Op == token.ARROW {
+					return impure, low
+				}
+			case ssa.CallInstruction: // *ssa.Call and *ssa.Defer
+				callee, calleeLow := a.callPurity(fn, instr.Common())
+				p, low = min(p, callee), min(low, calleeLow)
+				if p == impure {
+					return impure, low
+				}
+			}
+		}
+	}
+	return p, low
+}
+
+func (a *purityAnalysis) callPurity(fn *ssa.Function, call *ssa.CallCommon) (purity, int) {
+	if builtin, ok := call.Value.(*ssa.Builtin); ok {
+		switch builtin.Name() {
+		case "append", "len", "cap", "min", "max", "complex", "real", "imag", "ssa:wrapnilchk":
+			return pureHigh, len(a.stack)
+		case "copy", "delete", "clear":
+			if isFresh(call.Args[0], nil) {
+				return pureHigh, len(a.stack)
+			}
+		}
+		return impure, len(a.stack) // print, println, close, recover
+	}
+	if callee := call.StaticCallee(); callee != nil {
+		// The parameters called by functions being analyzed are not known yet,
+		// so every function passed to them is assumed to be called
+		_, inProgress := a.index[callee]
+		f, low := a.of(callee)
+		p := f.Purity
+		args := call.Args
+		if callee.Signature.Recv() != nil {
+			args = args[1:]
+		}
+		for i, arg := range args {
+			if _, ok := arg.Type().Underlying().(*types.Signature); !ok {
+				continue
+			}
+			if inProgress || slices.Contains(f.Params, i) {
+				argPurity, argLow := a.valuePurity(fn, arg)
+				p, low = min(p, argPurity), min(low, argLow)
+			}
+		}
+		return p, low
+	}
+	if param, ok := call.Value.(*ssa.Parameter); ok && !call.IsInvoke() {
+		// The callers decide, with the functions they pass
+		if i := paramIndex(fn, param); i >= 0 {
+			if !slices.Contains(a.params[fn], i) {
+				a.params[fn] = append(a.params[fn], i)
+			}
+			return pureHigh, len(a.stack)
+		}
+	}
+	return pureLow, len(a.stack) // function values and interface methods
+}
+
+// valuePurity returns the purity of calling the function value v within fn
+func (a *purityAnalysis) valuePurity(fn *ssa.Function, v ssa.Value) (purity, int) {
+	var callee *ssa.Function
+	switch v := v.(type) {
+	case *ssa.Function:
+		callee = v
+	case *ssa.MakeClosure:
+		callee = v.Fn.(*ssa.Function)
+	case *ssa.ChangeType:
+		return a.valuePurity(fn, v.X)
+	case *ssa.Const:
+		return pureHigh, len(a.stack) // nil
+	case *ssa.Parameter:
+		if i := paramIndex(fn, v); i >= 0 {
+			if !slices.Contains(a.params[fn], i) {
+				a.params[fn] = append(a.params[fn], i)
+			}
+			return pureHigh, len(a.stack)
+		}
+		return pureLow, len(a.stack)
+	default:
+		return pureLow, len(a.stack)
+	}
+	f, low := a.of(callee)
+	if len(f.Params) > 0 {
+		// Its own calls depend on arguments that are not known here
+		return min(f.Purity, pureLow), low
+	}
+	return f.Purity, low
+}
+
+// paramIndex returns the index of param among the parameters of fn, excluding
+// the receiver, or -1 if it is the receiver or not a parameter of fn
+func paramIndex(fn *ssa.Function, param *ssa.Parameter) int {
+	i := slices.Index(fn.Params, param)
+	if fn.Signature.Recv() != nil {
+		i--
+	}
+	return i
+}
+
+// isFresh reports whether v points into memory allocated by the function itself,
+// so that writing to it is not visible to callers
+func isFresh(v ssa.Value, seen map[ssa.Value]bool) bool {
+	switch v := v.(type) {
+	case *ssa.Alloc, *ssa.MakeSlice, *ssa.MakeMap:
+		return true
+	case *ssa.FieldAddr:
+		return isFresh(v.X, seen)
+	case *ssa.IndexAddr:
+		return isFresh(v.X, seen)
+	case *ssa.Slice:
+		return isFresh(v.X, seen)
+	case *ssa.Call:
+		// append to fresh memory returns fresh memory
+		builtin, ok := v.Call.Value.(*ssa.Builtin)
+		return ok && builtin.Name() == "append" && isFresh(v.Call.Args[0], seen)
+	case *ssa.Phi:
+		if seen[v] {
+			return true // loops, the other edges decide
+		}
+		if seen == nil {
+			seen = make(map[ssa.Value]bool)
+		}
+		seen[v] = true
+		for _, edge := range v.Edges {
+			if !isFresh(edge, seen) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// checkPurity reports the call on top of stack, whose results are discarded, if
+// the called function is inferred as pure
+func (l *Linter) checkPurity(pass *analysis.Pass, fn *types.Func, purities map[*types.Func]purityFact, stack []ast.Node) {
+	if fn.Type().(*types.Signature).Results().Len() == 0 {
+		return
+	}
+	if slices.Contains(l.settings.Purity.Allow, fn.FullName()) {
+		return
+	}
+
+	f, ok := lookupPurity(pass, fn, purities)
+	if !ok {
+		return
+	}
+	p := f.Purity
+	call := stack[len(stack)-1].(*ast.CallExpr)
+	for _, i := range f.Params {
+		if i < len(call.Args) {
+			p = min(p, argPurity(pass, call.Args[i], purities))
+		}
+	}
+	if p == impure || p < l.minConfidence() {
+		return
+	}
+	if l.hasReturnValueReceiver(pass.TypesInfo, stack) {
+		return
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:      stack[len(stack)-1].Pos(),
+		Category: "mustreceive",
+		Message:  fmt.Sprintf("%s has no side effects, discarding its results makes the call useless (confidence: %s)", fn.FullName(), p),
+	})
+}
+
+// lookupPurity returns the purity of fn, inferred in this package or imported as a fact
+func lookupPurity(pass *analysis.Pass, fn *types.Func, purities map[*types.Func]purityFact) (purityFact, bool) {
+	if f, ok := purities[fn]; ok {
+		return f, true
+	}
+	var fact purityFact
+	ok := pass.ImportObjectFact(fn, &fact)
+	return fact, ok
+}
+
+// argPurity returns the purity of calling the function passed as arg, which is only
+// known for functions referred to by name
+func argPurity(pass *analysis.Pass, arg ast.Expr, purities map[*types.Func]purityFact) purity {
+	if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.IsNil() {
+		return pureHigh
+	}
+	var ident *ast.Ident
+	switch e := ast.Unparen(arg).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		if _, ok := pass.TypesInfo.Selections[e]; ok {
+			return pureLow // method values
+		}
+		ident = e.Sel
+	default:
+		return pureLow
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok {
+		return pureLow
+	}
+	f, ok := lookupPurity(pass, fn.Origin(), purities)
+	if !ok {
+		return impure
+	}
+	if len(f.Params) > 0 {
+		return min(f.Purity, pureLow)
+	}
+	return f.Purity
+}
+
+func (l *Linter) minConfidence() purity {
+	if p, ok := confidences[l.settings.Purity.MinConfidence]; ok {
+		return p
+	}
+	return pureHigh
+}
@@ -0,0 +1,118 @@
+package mustreceive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// useIndex lists the positions where each object is used, built on first access
+type useIndex struct {
+	pass *analysis.Pass
+	uses map[types.Object][]token.Pos
+}
+
+func newUseIndex(pass *analysis.Pass) *useIndex {
+	return &useIndex{pass: pass}
+}
+
+// usedAfter reports whether obj is used after the statement from start to end that
+// assigns it, with the enclosing nodes of the statement in stack. Within a loop that obj
+// is declared outside of, the uses before the statement run again on the next
+// iteration, so any use within the outermost such loop counts.
+func (idx *useIndex) usedAfter(obj types.Object, start, end token.Pos, stack []ast.Node) bool {
+	if idx.uses == nil {
+		idx.uses = make(map[types.Object][]token.Pos)
+		for ident, o := range idx.pass.TypesInfo.Uses {
+			idx.uses[o] = append(idx.uses[o], ident.Pos())
+		}
+		for _, list := range idx.uses {
+			sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+		}
+	}
+
+	from := end
+loops:
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			break loops
+		case *ast.ForStmt, *ast.RangeStmt:
+			if obj.Pos() < n.Pos() {
+				from = n.Pos()
+			}
+		}
+	}
+
+	list := idx.uses[obj]
+	for i := sort.Search(len(list), func(i int) bool { return list[i] >= from }); i < len(list); i++ {
+		if list[i] < start || list[i] > end {
+			return true
+		}
+	}
+	return false
+}
+
+// checkResults reports required results of the call on top of stack that are
+// assigned to the blank identifier or never used afterwards
+func checkResults(pass *analysis.Pass, fn *types.Func, config *FuncConfig, stack []ast.Node, uses *useIndex) {
+	receivers, end := resultReceivers(pass.TypesInfo, stack)
+	if receivers == nil {
+		return
+	}
+	for _, index := range config.Results {
+		if index < 0 || index >= len(receivers) {
+			continue // not a result of this call
+		}
+		ident, ok := receivers[index].(*ast.Ident)
+		if !ok {
+			continue // stored in a field or an element, e.g. s.span = ...
+		}
+		if ident.Name == "_" {
+			reportCall(pass, fn, config, ident.Pos(), fmt.Sprintf("%s must receive result %d", fn.FullName(), index), []int{index})
+			continue
+		}
+		if obj := pass.TypesInfo.ObjectOf(ident); obj != nil && !uses.usedAfter(obj, ident.Pos(), end, stack) {
+			reportCall(pass, fn, config, ident.Pos(), fmt.Sprintf("%s result %d is received by %s but never used", fn.FullName(), index, ident.Name), []int{index})
+		}
+	}
+}
+
+// resultReceivers returns the expressions receiving the results of the call on top of
+// stack, indexed by result, and the end of the receiving statement
+func resultReceivers(info *types.Info, stack []ast.Node) ([]ast.Expr, token.Pos) {
+	i, child := receiverParent(info, stack)
+	if i < 0 {
+		return nil, token.NoPos
+	}
+
+	var lhs, rhs []ast.Expr
+	var end token.Pos
+	switch n := stack[i].(type) {
+	case *ast.AssignStmt:
+		lhs, rhs, end = n.Lhs, n.Rhs, n.End()
+	case *ast.ValueSpec:
+		lhs = make([]ast.Expr, len(n.Names))
+		for i, name := range n.Names {
+			lhs[i] = name
+		}
+		rhs, end = n.Values, n.End()
+	default:
+		return nil, token.NoPos
+	}
+
+	// A multi-value call is the only value on the right
+	if len(rhs) == 1 {
+		return lhs, end
+	}
+	for i, value := range rhs {
+		if value == child && i < len(lhs) {
+			return lhs[i : i+1], end
+		}
+	}
+	return nil, token.NoPos
+}
@@ -0,0 +1,21 @@
+package client
+
+import (
+	"context"
+
+	"testdata/annotated/pool"
+)
+
+func Use(ctx context.Context, p pool.Pool) {
+	conn, err := pool.Acquire(ctx)
+	if err == nil {
+		conn.Release()
+	}
+	pool.Acquire(ctx) // want "testdata/annotated/pool.Acquire must receive its return value"
+
+	ctx, release := pool.Lease(ctx)
+	defer release()
+	ctx, _ = pool.Lease(ctx) // want "testdata/annotated/pool.Lease must receive result 1"
+
+	p.Get(ctx) // want `\(testdata/annotated/pool.Pool\).Get must receive its return value`
+}
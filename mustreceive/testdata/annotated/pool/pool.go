@@ -0,0 +1,43 @@
+package pool
+
+import (
+	"context"
+)
+
+type Conn struct{}
+
+func (c *Conn) Release() {}
+
+// Acquire returns a connection that must be released
+//
+//lint:mustreceive
+func Acquire(ctx context.Context) (*Conn, error) { // want Acquire:"mustreceive"
+	return &Conn{}, nil
+}
+
+// Lease returns the context of a lease and its release function
+//
+//lint:mustreceive 1
+func Lease(ctx context.Context) (context.Context, func()) { // want Lease:"mustreceive 1"
+	return ctx, func() {}
+}
+
+// Pool hands out connections
+type Pool interface {
+	// Get returns a connection that must be released
+	//
+	//lint:mustreceive
+	Get(ctx context.Context) (*Conn, error) // want Get:"mustreceive"
+}
+
+//lint:mustreceive 2
+func Invalid() (int, error) { // want `invalid result index "2" in //lint:mustreceive, the function has 2 results`
+	return 0, nil
+}
+
+//lint:mustreceive
+func NoResults() {} // want `//lint:mustreceive on a function without results`
+
+func internalUse(ctx context.Context) {
+	Acquire(ctx) // want "testdata/annotated/pool.Acquire must receive its return value"
+}
@@ -0,0 +1,36 @@
+package fixes
+
+import (
+	"context"
+
+	"github.com/theplant/appkit/logtracing"
+)
+
+func Declare(ctx context.Context) {
+	logtracing.StartSpan(ctx, "declare") // want "github.com/theplant/appkit/logtracing.StartSpan must receive its return value"
+}
+
+func Assign(ctx context.Context) {
+	var cancel context.CancelFunc
+	if ctx != nil {
+		context.WithCancel(ctx) // want "context.WithCancel must receive its return value"
+	}
+	_ = cancel
+}
+
+// Nested would shadow ctx with a declaration, so no fix is offered
+func Nested(ctx context.Context) {
+	if ctx != nil {
+		context.WithCancel(ctx) // want "context.WithCancel must receive its return value"
+	}
+}
+
+func Reuse(parent context.Context) {
+	ctx := parent
+	context.WithCancel(ctx) // want "context.WithCancel must receive its return value"
+	_ = ctx
+}
+
+func NotStatement(ctx context.Context) {
+	defer logtracing.StartSpan(ctx, "defer") // want "github.com/theplant/appkit/logtracing.StartSpan is called in a defer statement, which discards its return value"
+}
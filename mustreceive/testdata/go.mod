@@ -2,7 +2,12 @@ module testdata
 
 go 1.25.8
 
-require github.com/theplant/appkit v0.0.0-20250317082139-cf11351af1e5
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/theplant/appkit v0.0.0-20250317082139-cf11351af1e5
+	go.opentelemetry.io/otel v1.0.0
+	gorm.io/gorm v1.0.0
+)
 
 require (
 	github.com/go-kit/kit v0.12.1-0.20220826005032-a7ba4fa4e289 // indirect
@@ -15,7 +20,6 @@ require (
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/jjeffery/errors v1.0.3 // indirect
 	github.com/jjeffery/kv v0.8.1 // indirect
-	github.com/pkg/errors v0.9.1 // indirect
 	golang.org/x/net v0.36.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
@@ -23,3 +27,9 @@ require (
 	google.golang.org/grpc v1.56.3 // indirect
 	google.golang.org/protobuf v1.33.0 // indirect
 )
+
+// Stubs of the packages covered by the presets, see stubs
+replace (
+	go.opentelemetry.io/otel => ./stubs/go.opentelemetry.io/otel
+	gorm.io/gorm => ./stubs/gorm.io/gorm
+)
@@ -0,0 +1,94 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+
+	"github.com/theplant/appkit/logtracing"
+
+	"testdata/tracing/v2"
+)
+
+func DeferredEnd(ctx context.Context) {
+	ctx, span := logtracing.StartSpan(ctx, "good")
+	defer span.End()
+	_ = ctx
+}
+
+func EndOnAllPaths(ctx context.Context, fail bool) error {
+	_, span := logtracing.StartSpan(ctx, "good")
+	if fail {
+		span.End()
+		return errors.New("fail")
+	}
+	span.End()
+	return nil
+}
+
+func MissingEnd(ctx context.Context, fail bool) error {
+	_, span := logtracing.StartSpan(ctx, "bad") // want `github.com/theplant/appkit/logtracing.StartSpan result 1 is not released by span.End\(\) on all paths`
+	if fail {
+		return errors.New("fail") // want `this return statement may be reached without calling span.End\(\) for the span var defined on line 29`
+	}
+	span.End()
+	return nil
+}
+
+func NeverEnded(ctx context.Context) {
+	_, span := logtracing.StartSpan(ctx, "bad") // want `github.com/theplant/appkit/logtracing.StartSpan result 1 is not released by span.End\(\) on all paths`
+	_ = span
+} // want `this return statement may be reached without calling span.End\(\)`
+
+func Cancel(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	_ = ctx
+}
+
+func HandedOver(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, cancel
+}
+
+func DiscardedCancel(ctx context.Context) {
+	ctx, _ = context.WithCancel(ctx) // want "context.WithCancel result 1 must be received and released"
+	_ = ctx
+}
+
+func FinishInDefer(t *tracing.Tracer, ctx context.Context) (err error) {
+	ctx, span := t.Start(ctx, "good")
+	defer func() { tracing.Finish(ctx, err) }()
+	_ = span
+	return nil
+}
+
+func MissingFinish(t *tracing.Tracer, ctx context.Context) error {
+	ctx, span := t.Start(ctx, "bad") // want `\(\*testdata/tracing/v2.Tracer\).Start result 0 is not released by testdata/tracing/v2.Finish on all paths`
+	_ = span
+	return nil // want `this return statement may be reached without calling testdata/tracing/v2.Finish for the ctx var`
+}
+
+func ParenthesizedMissingEnd(ctx context.Context, fail bool) error {
+	_, span := (logtracing.StartSpan(ctx, "bad")) // want `github.com/theplant/appkit/logtracing.StartSpan result 1 is not released by span.End\(\) on all paths`
+	if fail {
+		return errors.New("fail") // want `this return statement may be reached without calling span.End\(\) for the span var defined on line 72`
+	}
+	span.End()
+	return nil
+}
+
+type ender interface{ End() }
+
+func ConvertedMissingEnd(fail bool) error {
+	span := ender(tracing.NewSpan("bad")) // want `testdata/tracing/v2.NewSpan result 0 is not released by span.End\(\) on all paths`
+	if fail {
+		return errors.New("fail") // want `this return statement may be reached without calling span.End\(\) for the span var defined on line 83`
+	}
+	span.End()
+	return nil
+}
+
+func ConvertedEnd() {
+	span := ender((tracing.NewSpan("good")))
+	defer span.End()
+}
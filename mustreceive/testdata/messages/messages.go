@@ -0,0 +1,14 @@
+package messages
+
+import (
+	"context"
+
+	"github.com/theplant/appkit/logtracing"
+)
+
+func Messages(ctx context.Context) {
+	logtracing.StartSpan(ctx, "bad") // want `StartSpan starts a span, results 0, 1 must be received, see the tracing guide`
+
+	ctx, _ = logtracing.StartSpan(ctx, "bad") // want `StartSpan starts a span, results 1 must be received, see the tracing guide`
+	_ = ctx
+}
@@ -0,0 +1,27 @@
+package presets
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/theplant/appkit/logtracing"
+)
+
+func Stdlib(ctx context.Context, name string, ids []int) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	strings.TrimSpace(name)    // want "strings.TrimSpace must receive its return value"
+	slices.Delete(ids, 0, 1)   // want "slices.Delete must receive its return value"
+	time.NewTimer(time.Second) // want "time.NewTimer must receive its return value"
+	context.WithCancel(ctx)    // want "context.WithCancel must receive its return value"
+	ids = slices.Insert(ids, 0, 1)
+	_ = ids
+}
+
+func UserEntries(ctx context.Context) {
+	// User entries are checked alongside the presets
+	logtracing.StartSpan(ctx, "user") // want "github.com/theplant/appkit/logtracing.StartSpan must receive its return value"
+}
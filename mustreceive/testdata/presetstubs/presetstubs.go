@@ -0,0 +1,35 @@
+package presetstubs
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+func PkgErrors(err error) error {
+	errors.Wrap(err, "load") // want "github.com/pkg/errors.Wrap must receive its return value"
+	errors.New("missing")    // want "github.com/pkg/errors.New must receive its return value"
+	return errors.WithStack(err)
+}
+
+func Gorm(db *gorm.DB, users any) error {
+	db.Where("active = ?", true) // want `\(\*gorm.io/gorm.DB\).Where must receive its return value`
+	db.Model(users)              // want `\(\*gorm.io/gorm.DB\).Model must receive its return value`
+	return db.Find(users).Error
+}
+
+func Otel(ctx context.Context, provider trace.TracerProvider, span trace.Span) {
+	otel.Tracer("presets")                                 // want "go.opentelemetry.io/otel.Tracer must receive its return value"
+	provider.Tracer("presets")                             // want `\(go.opentelemetry.io/otel/trace.TracerProvider\).Tracer must receive its return value`
+	trace.ContextWithSpan(ctx, span)                       // want "go.opentelemetry.io/otel/trace.ContextWithSpan must receive its return value"
+	trace.ContextWithSpanContext(ctx, trace.SpanContext{}) // want "go.opentelemetry.io/otel/trace.ContextWithSpanContext must receive its return value"
+
+	tracer := provider.Tracer("presets")
+	tracer.Start(ctx, "span") // want `\(go.opentelemetry.io/otel/trace.Tracer\).Start must receive its return value`
+	ctx, span = tracer.Start(ctx, "span")
+	defer span.End()
+	_ = ctx
+}
@@ -0,0 +1,136 @@
+package purity
+
+import (
+	"strconv"
+	"strings"
+)
+
+var calls int
+
+type stringer interface {
+	String() string
+}
+
+type counter struct {
+	n int
+}
+
+func double(x int) int { // want double:"pure\\(high\\)"
+	return x * 2
+}
+
+func reversed(xs []int) []int { // want reversed:"pure\\(high\\)"
+	out := make([]int, 0, len(xs))
+	for i := len(xs) - 1; i >= 0; i-- {
+		out = append(out, xs[i])
+	}
+	return out
+}
+
+func validate(name string) error { // want validate:"pure\\(high\\)"
+	if name == "" {
+		return strconv.ErrSyntax
+	}
+	return nil
+}
+
+func counted(x int) int {
+	calls++
+	return x
+}
+
+func (c *counter) inc() int {
+	c.n++
+	return c.n
+}
+
+func (c *counter) value() int { // want value:"pure\\(high\\)"
+	return c.n
+}
+
+func notify(ch chan int, x int) int {
+	ch <- x
+	return x
+}
+
+// apply calls its function parameter, each call decides with the function it passes
+func apply(f func(int) int, x int) int { // want apply:"pure\\(high, calls \\[0\\]\\)"
+	return f(x)
+}
+
+func applyTwice(f func(int) int, x int) int { // want applyTwice:"pure\\(high, calls \\[0\\]\\)"
+	return apply(f, apply(f, x))
+}
+
+// describe calls an interface method, which is assumed to be pure
+func describe(s stringer) string { // want describe:"pure\\(low\\)"
+	return s.String()
+}
+
+// ping and pong are mutually recursive, and ping writes a global after the call,
+// so both have side effects
+func ping(n int) int {
+	if n == 0 {
+		return 0
+	}
+	r := pong(n - 1)
+	calls++
+	return r
+}
+
+func pong(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return ping(n - 1)
+}
+
+// isEven and isOdd are mutually recursive without side effects
+func isEven(n uint) bool { // want isEven:"pure\\(high\\)"
+	if n == 0 {
+		return true
+	}
+	return isOdd(n - 1)
+}
+
+func isOdd(n uint) bool { // want isOdd:"pure\\(high\\)"
+	if n == 0 {
+		return false
+	}
+	return isEven(n - 1)
+}
+
+func mustPositive(x int) int {
+	if x < 0 {
+		panic("negative")
+	}
+	return x
+}
+
+func Calls(c *counter, ch chan int) {
+	double(1)                              // want `testdata/purity.double has no side effects, discarding its results makes the call useless \(confidence: high\)`
+	_ = double(2)                          // want `testdata/purity.double has no side effects`
+	reversed([]int{1, 2})                  // want `testdata/purity.reversed has no side effects`
+	validate("")                           // want `testdata/purity.validate has no side effects`
+	c.value()                              // want `\(\*testdata/purity.counter\).value has no side effects`
+	apply(double, 1)                       // want `testdata/purity.apply has no side effects, discarding its results makes the call useless \(confidence: high\)`
+	applyTwice(double, 1)                  // want `testdata/purity.applyTwice has no side effects, discarding its results makes the call useless \(confidence: high\)`
+	apply(func(x int) int { return x }, 1) // want `testdata/purity.apply has no side effects, discarding its results makes the call useless \(confidence: low\)`
+	describe(nil)                          // want `testdata/purity.describe has no side effects, discarding its results makes the call useless \(confidence: low\)`
+	strings.TrimPrefix("x", "y")           // want "strings.TrimPrefix has no side effects, discarding its results makes the call useless \\(confidence: high\\)"
+	strings.TrimSpace("x")                 // want "strings.TrimSpace has no side effects, discarding its results makes the call useless \\(confidence: high\\)"
+	strings.HasPrefix("x", "y")            // allowed
+
+	// Functions with side effects
+	counted(1)
+	c.inc()
+	notify(ch, 1)
+	ping(1)
+	pong(1)
+	mustPositive(1)
+	apply(counted, 1)
+	isOdd(1) // want `testdata/purity.isOdd has no side effects, discarding its results makes the call useless \(confidence: high\)`
+
+	x := double(3)
+	_ = x
+}
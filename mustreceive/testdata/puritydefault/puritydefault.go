@@ -0,0 +1,27 @@
+package puritydefault
+
+import "strings"
+
+type stringer interface {
+	String() string
+}
+
+func double(x int) int { // want double:"pure\\(high\\)"
+	return x * 2
+}
+
+func apply(f func(int) int, x int) int { // want apply:"pure\\(high, calls \\[0\\]\\)"
+	return f(x)
+}
+
+func describe(s stringer) string { // want describe:"pure\\(low\\)"
+	return s.String()
+}
+
+// Only high confidence calls are reported by default
+func Calls(s stringer) { // want Calls:"pure\\(low\\)"
+	strings.TrimSpace(" x ") // want `strings.TrimSpace has no side effects, discarding its results makes the call useless \(confidence: high\)`
+	apply(double, 1)         // want `testdata/puritydefault.apply has no side effects, discarding its results makes the call useless \(confidence: high\)`
+	apply(func(x int) int { return x }, 1)
+	describe(s)
+}
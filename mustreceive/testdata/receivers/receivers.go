@@ -0,0 +1,22 @@
+package receivers
+
+import (
+	"testdata/tracing/v2"
+)
+
+type spanHolder struct {
+	span *tracing.Span
+}
+
+func end(span *tracing.Span) {
+	span.End()
+}
+
+func returned() *tracing.Span {
+	return tracing.NewSpan("returned")
+}
+
+func NotReceivers() {
+	end(tracing.NewSpan("argument"))                   // want "testdata/tracing/v2.NewSpan must receive its return value"
+	_ = spanHolder{span: tracing.NewSpan("composite")} // want "testdata/tracing/v2.NewSpan must receive its return value"
+}
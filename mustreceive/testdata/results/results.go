@@ -0,0 +1,69 @@
+package results
+
+import (
+	"context"
+
+	"github.com/theplant/appkit/logtracing"
+)
+
+func GoodUsage(ctx context.Context) {
+	ctx, span := logtracing.StartSpan(ctx, "good1")
+	defer span.End()
+
+	_, span2 := logtracing.StartSpan(ctx, "good2")
+	span2.End()
+
+	var _, span3 = logtracing.StartSpan(ctx, "good3")
+	span3.End()
+}
+
+func BlankResult(ctx context.Context) {
+	ctx, _ = logtracing.StartSpan(ctx, "bad1")   // want "github.com/theplant/appkit/logtracing.StartSpan must receive result 1"
+	_, _ = logtracing.StartSpan(ctx, "bad2")     // want "github.com/theplant/appkit/logtracing.StartSpan must receive its return value"
+	ctx, _ = (logtracing.StartSpan(ctx, "bad3")) // want "github.com/theplant/appkit/logtracing.StartSpan must receive result 1"
+	_ = ctx
+}
+
+type holder struct {
+	span interface{ End() }
+}
+
+func StoredResult(ctx context.Context, h *holder) {
+	_, h.span = logtracing.StartSpan(ctx, "stored")
+}
+
+func UnusedResult(ctx context.Context) {
+	_, span := logtracing.StartSpan(ctx, "first")
+	span.End()
+
+	_, span = logtracing.StartSpan(ctx, "second") // want "github.com/theplant/appkit/logtracing.StartSpan result 1 is received by span but never used"
+}
+
+// The span ended at the top of the loop is the one started in the previous iteration
+func LoopResult(ctx context.Context, names []string) {
+	_, span := logtracing.StartSpan(ctx, "first")
+	for _, name := range names {
+		span.End()
+		_, span = logtracing.StartSpan(ctx, name)
+	}
+	span.End()
+}
+
+// span is declared within the loop, each iteration has its own
+func LoopUnusedResult(ctx context.Context, names []string) {
+	for _, name := range names {
+		_, span := logtracing.StartSpan(ctx, name)
+		span.End()
+		_, span = logtracing.StartSpan(ctx, name) // want "github.com/theplant/appkit/logtracing.StartSpan result 1 is received by span but never used"
+	}
+}
+
+func LoopLastResult(ctx context.Context, n int) {
+	var span interface{ End() }
+	for i := 0; i < n; i++ {
+		if span != nil {
+			span.End()
+		}
+		_, span = logtracing.StartSpan(ctx, "iteration")
+	}
+}
@@ -0,0 +1,57 @@
+package stalecontext
+
+import (
+	"context"
+
+	"github.com/theplant/appkit/logtracing"
+)
+
+func call(ctx context.Context) {}
+
+func Replaced(ctx context.Context) {
+	ctx, span := logtracing.StartSpan(ctx, "replaced")
+	defer span.End()
+
+	call(ctx)
+}
+
+func Stale(ctx context.Context) {
+	newCtx, span := logtracing.StartSpan(ctx, "stale")
+	defer span.End()
+
+	call(newCtx)
+	call(ctx) // want `ctx is stale after github.com/theplant/appkit/logtracing.StartSpan returned newCtx on line 19, use newCtx instead`
+	go func() {
+		call(ctx) // want `ctx is stale after github.com/theplant/appkit/logtracing.StartSpan returned newCtx on line 19, use newCtx instead`
+	}()
+}
+
+func Discarded(ctx context.Context) {
+	_, span := logtracing.StartSpan(ctx, "discarded") // want `github.com/theplant/appkit/logtracing.StartSpan returns a new context, which is discarded while ctx is used afterwards`
+	defer span.End()
+
+	call(ctx)
+}
+
+func DiscardedUnused(ctx context.Context) {
+	_, span := logtracing.StartSpan(ctx, "unused")
+	defer span.End()
+}
+
+func Reassigned(ctx context.Context) {
+	newCtx, span := logtracing.StartSpan(ctx, "reassigned")
+	defer span.End()
+
+	ctx = context.WithValue(ctx, "key", "value") // want `ctx is stale after github.com/theplant/appkit/logtracing.StartSpan returned newCtx on line 42, use newCtx instead`
+	call(ctx)
+	call(newCtx)
+}
+
+func Scoped(ctx context.Context) {
+	if true {
+		newCtx, span := logtracing.StartSpan(ctx, "scoped")
+		defer span.End()
+		call(newCtx)
+	}
+	call(ctx)
+}
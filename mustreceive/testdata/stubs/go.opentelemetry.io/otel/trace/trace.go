@@ -0,0 +1,22 @@
+// Package trace stubs the go.opentelemetry.io/otel/trace API covered by the otel preset
+package trace
+
+import "context"
+
+type Span interface {
+	End()
+}
+
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+func ContextWithSpan(parent context.Context, span Span) context.Context { return parent }
+
+type SpanContext struct{}
+
+func ContextWithSpanContext(parent context.Context, sc SpanContext) context.Context { return parent }
@@ -19,3 +19,13 @@ func TestAliasUsage() {
 	_ = span2
 	_ = newCtx
 }
+
+type localTracer struct{}
+
+func (localTracer) StartSpan(ctx context.Context, name string) {}
+
+func TestShadowedPackageName() {
+	// A local variable shadowing the package name is not checked
+	log := localTracer{}
+	log.StartSpan(context.Background(), "shadowed")
+}
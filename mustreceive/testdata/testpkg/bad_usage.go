@@ -16,6 +16,23 @@ func BadUsageExamples() {
 	logtracing.StartSpan(ctx, "bad2") // want "github.com/theplant/appkit/logtracing.StartSpan must receive its return value"
 }
 
+func GoAndDeferExamples() {
+	ctx := context.Background()
+
+	// Calls in go and defer statements always discard their results
+	go logtracing.StartSpan(ctx, "go")       // want "github.com/theplant/appkit/logtracing.StartSpan is called in a go statement, which discards its return value"
+	defer logtracing.StartSpan(ctx, "defer") // want "github.com/theplant/appkit/logtracing.StartSpan is called in a defer statement, which discards its return value"
+
+	// Inside a function literal the call is a plain statement
+	go func() {
+		logtracing.StartSpan(ctx, "in goroutine") // want "github.com/theplant/appkit/logtracing.StartSpan must receive its return value"
+	}()
+	defer func() {
+		ctx, span := logtracing.StartSpan(ctx, "in defer")
+		_, _ = ctx, span
+	}()
+}
+
 func GoodUsageExamples() {
 	ctx := context.Background()
 
@@ -19,3 +19,9 @@ func TestDotImportUsage() {
 	_ = span2
 	_ = newCtx
 }
+
+func TestShadowedDotImport() {
+	// A local function shadowing the dot-imported one is not checked
+	StartSpan := func(ctx context.Context, name string) {}
+	StartSpan(context.Background(), "shadowed")
+}
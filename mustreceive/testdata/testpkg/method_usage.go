@@ -0,0 +1,39 @@
+package testpkg
+
+import (
+	"context"
+	"database/sql"
+
+	"testdata/tracing/v2"
+)
+
+type service struct {
+	db      *sql.DB
+	t       *tracing.Tracer
+	starter tracing.Starter
+}
+
+func (s *service) tracer() *tracing.Tracer {
+	return s.t
+}
+
+func TestMethodUsage(s *service) {
+	ctx := context.Background()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	_, _ = tx, err
+	s.db.BeginTx(ctx, nil) // want `\(\*database/sql.DB\).BeginTx must receive its return value`
+
+	ctx, span := s.t.Start(ctx, "good")
+	defer span.End()
+	s.t.Start(ctx, "bad")        // want `\(\*testdata/tracing/v2.Tracer\).Start must receive its return value`
+	s.tracer().Start(ctx, "bad") // want `\(\*testdata/tracing/v2.Tracer\).Start must receive its return value`
+
+	// Interface methods
+	ctx, span2 := s.starter.Start(ctx, "good")
+	defer span2.End()
+	s.starter.Start(ctx, "bad") // want `\(testdata/tracing/v2.Starter\).Start must receive its return value`
+
+	// Package-level functions with the same name are not methods
+	tracing.Start(ctx, "other") // want "testdata/tracing/v2.Start must receive its return value"
+}
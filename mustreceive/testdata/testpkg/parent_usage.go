@@ -0,0 +1,47 @@
+package testpkg
+
+import (
+	"testdata/tracing/v2"
+)
+
+type ownedSpan tracing.Span
+
+type ender interface{ End() }
+
+func keep(span *tracing.Span) *tracing.Span { return span }
+
+func convertedSpan() ender {
+	return ender(tracing.NewSpan("returned conversion"))
+}
+
+func TestParentUsage() {
+	// Parentheses and conversions pass the result to their own parent
+	a := (tracing.NewSpan("parenthesized"))
+	// Not gofmt-ed, which would drop the nested parentheses
+	b := ((tracing.NewSpan("double parenthesized")))
+	c := (*ownedSpan)(tracing.NewSpan("conversion"))
+	var d ender = ender((tracing.NewSpan("parenthesized conversion")))
+	e := keep(tracing.NewSpan("argument"))
+
+	// Init statements assign like any other statement
+	if s := tracing.NewSpan("if init"); s != nil {
+		s.End()
+	}
+	switch s := (tracing.NewSpan("switch init")); s {
+	}
+	for s := tracing.NewSpan("for init"); s != nil; s = nil {
+	}
+
+	// Results that are only operands are thrown away
+	(tracing.NewSpan("parenthesized statement"))               // want "testdata/tracing/v2.NewSpan must receive its return value"
+	name := tracing.NewSpan("selector").Name()                 // want "testdata/tracing/v2.NewSpan must receive its return value"
+	ok := tracing.NewSpan("comparison") != nil                 // want "testdata/tracing/v2.NewSpan must receive its return value"
+	length := len(tracing.NewSpan("nested").Name())            // want "testdata/tracing/v2.NewSpan must receive its return value"
+	_ = ender(tracing.NewSpan("blank conversion"))             // want "testdata/tracing/v2.NewSpan must receive its return value"
+	_ = (*ownedSpan)((tracing.NewSpan("blank parenthesized"))) // want "testdata/tracing/v2.NewSpan must receive its return value"
+	if tracing.NewSpan("if condition") == nil {                // want "testdata/tracing/v2.NewSpan must receive its return value"
+		return
+	}
+
+	_, _, _, _, _, _, _, _ = a, b, c, d, e, name, ok, length
+}
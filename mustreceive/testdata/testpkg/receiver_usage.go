@@ -0,0 +1,37 @@
+package testpkg
+
+import (
+	"context"
+
+	"github.com/theplant/appkit/logtracing"
+
+	"testdata/tracing/v2"
+)
+
+type spanHolder struct {
+	span *tracing.Span
+}
+
+func returnedSpan(ctx context.Context) (context.Context, interface{ End() }) {
+	return logtracing.StartSpan(ctx, "returned")
+}
+
+func consume(ctx context.Context, span interface{ End() }) {}
+
+func TestReceiverUsage() {
+	ctx := context.Background()
+
+	// Passing the results to another call receives them
+	consume(logtracing.StartSpan(ctx, "argument"))
+	consume((logtracing.StartSpan(ctx, "parenthesized")))
+
+	// So does storing them in a composite literal
+	holder := spanHolder{span: tracing.NewSpan("field")}
+	spans := []*tracing.Span{tracing.NewSpan("element")}
+
+	// Assigning every result to the blank identifier discards them
+	_, _ = logtracing.StartSpan(ctx, "blank") // want "github.com/theplant/appkit/logtracing.StartSpan must receive its return value"
+	_ = tracing.NewSpan("blank")              // want "testdata/tracing/v2.NewSpan must receive its return value"
+
+	_, _ = holder, spans
+}
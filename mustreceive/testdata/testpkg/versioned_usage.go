@@ -0,0 +1,15 @@
+package testpkg
+
+import (
+	"context"
+
+	"testdata/tracing/v2"
+)
+
+func TestVersionedUsage() {
+	ctx := context.Background()
+	ctx, end := tracing.Start(ctx, "test1")
+	defer end()
+
+	tracing.Start(ctx, "test2") // want "testdata/tracing/v2.Start must receive its return value"
+}